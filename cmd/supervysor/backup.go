@@ -8,6 +8,8 @@ import (
 
 	"github.com/KYVENetwork/supervysor/backup"
 	"github.com/KYVENetwork/supervysor/cmd/supervysor/helpers"
+	"github.com/KYVENetwork/supervysor/hooks"
+	nodeHelpers "github.com/KYVENetwork/supervysor/node/helpers"
 	"github.com/spf13/cobra"
 )
 
@@ -40,7 +42,7 @@ var backupCmd = &cobra.Command{
 			return
 		}
 
-		config, err := helpers.LoadConfig(home)
+		config, err := nodeHelpers.LoadConfig(home)
 		if err != nil {
 			logger.Error("failed to load tendermint config", "err", err)
 			return
@@ -54,6 +56,22 @@ var backupCmd = &cobra.Command{
 		}
 		defer blockStoreDB.Close()
 
+		// Backup hooks are taken from the config if the supervysor was already initialized.
+		var runner *hooks.Runner
+		if supervysorConfig, err := getSupervysorConfig(); err == nil {
+			if runner, err = hooks.NewRunner(logger, supervysorConfig); err != nil {
+				logger.Error("invalid hooks", "err", err)
+				return
+			}
+			runner.Home = home
+		}
+		env := hooks.Env{NodeHeight: int(blockStore.Height())}
+
+		if err := runner.Run(hooks.EventBackup, hooks.PhasePre, env); err != nil {
+			logger.Error("backup aborted", "err", err)
+			return
+		}
+
 		if destPath == "" {
 			logger.Info("height", "h", blockStore.Height())
 			d, err := helpers.CreateDestPath(backupDir, blockStore.Height())
@@ -94,5 +112,9 @@ var backupCmd = &cobra.Command{
 				return
 			}
 		}
+
+		if err := runner.Run(hooks.EventBackup, hooks.PhasePost, env); err != nil {
+			logger.Error("post-backup hook failed", "err", err)
+		}
 	},
 }
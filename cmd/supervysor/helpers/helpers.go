@@ -7,12 +7,10 @@ import (
 	"path/filepath"
 	"strconv"
 
-	"github.com/spf13/viper"
-
+	"github.com/KYVENetwork/supervysor/monitoring"
 	"github.com/KYVENetwork/supervysor/types"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
-	cfg "github.com/tendermint/tendermint/config"
 )
 
 func CreateDestPath(backupDir string, latestHeight int64) (string, error) {
@@ -94,63 +92,64 @@ func GetSupervysorDir() (string, error) {
 	return supervysorDir, nil
 }
 
-func LoadConfig(homeDir string) (config *cfg.Config, err error) {
-	config = cfg.DefaultConfig()
-
-	viper.SetConfigName("config")
-	viper.SetConfigType("toml")
-	viper.AddConfigPath(homeDir)
-	viper.AddConfigPath(filepath.Join(homeDir, "config"))
-
-	if err := viper.ReadInConfig(); err != nil {
-		return nil, err
-	}
-
-	if err := viper.Unmarshal(config); err != nil {
-		return nil, err
-	}
-
-	config.SetRoot(homeDir)
-
-	return config, nil
-}
-
 func NewMetrics(reg prometheus.Registerer) *types.Metrics {
 	m := &types.Metrics{
-		PoolHeight: prometheus.NewGauge(prometheus.GaugeOpts{
-			Namespace: "supervysor",
-			Name:      "pool_height",
-			Help:      "Height of the specified KYVE data pool.",
-		}),
-		NodeHeight: prometheus.NewGauge(prometheus.GaugeOpts{
-			Namespace: "supervysor",
-			Name:      "node_height",
-			Help:      "Height of the running data source node.",
-		}),
-		MaxHeight: prometheus.NewGauge(prometheus.GaugeOpts{
-			Namespace: "supervysor",
-			Name:      "max_height",
-			Help:      "Maximum height of node until Ghost Mode enabling.",
-		}),
-		MinHeight: prometheus.NewGauge(prometheus.GaugeOpts{
-			Namespace: "supervysor",
-			Name:      "min_height",
-			Help:      "Minimum height of node until Normal Mode enabling.",
-		}),
-		DataDirSize: prometheus.NewGauge(prometheus.GaugeOpts{
-			Namespace: "supervysor",
-			Name:      "data_dir_size",
-			Help:      "Size of data dir in --home dir.",
-		}),
+		PoolHeight:               monitoring.PoolHeight.NewGauge(),
+		NodeHeight:               monitoring.NodeHeight.NewGauge(),
+		MaxHeight:                monitoring.MaxHeight.NewGauge(),
+		MinHeight:                monitoring.MinHeight.NewGauge(),
+		DataDirSize:              monitoring.DataDirSize.NewGauge(),
+		PoolOutageDuration:       monitoring.PoolOutageDuration.NewGauge(),
+		PoolStatus:               monitoring.PoolStatus.NewGaugeVec(),
+		PoolEventsConnected:      monitoring.PoolEventsConnected.NewGauge(),
+		PoolVelocity:             monitoring.PoolVelocity.NewGauge(),
+		NodeVelocity:             monitoring.NodeVelocity.NewGauge(),
+		CatchUpTime:              monitoring.CatchUpTime.NewGauge(),
+		GhostModeLeak:            monitoring.GhostModeLeak.NewGauge(),
+		GhostModeLeaks:           monitoring.GhostModeLeaks.NewCounter(),
+		GhostIsolationUnverified: monitoring.GhostIsolationUnverified.NewCounter(),
+		PoolEndpointHeight:       monitoring.PoolEndpointHeight.NewGaugeVec(),
+		PoolEndpointLatency:      monitoring.PoolEndpointLatency.NewGaugeVec(),
+		PoolEndpointOutlier:      monitoring.PoolEndpointOutlier.NewGaugeVec(),
+		PoolEndpointErrors:       monitoring.PoolEndpointErrors.NewCounterVec(),
+		CurrentMode:              monitoring.CurrentMode.NewGaugeVec(),
+		ModeSeconds:              monitoring.ModeSeconds.NewCounterVec(),
+		ModeTransitions:          monitoring.ModeTransitions.NewCounterVec(),
+		SwitchDowntime:           monitoring.SwitchDowntime.NewHistogramVec(),
+		PruneRuns:                monitoring.PruneRuns.NewCounter(),
+		PrunedBlocks:             monitoring.PrunedBlocks.NewCounter(),
+		PruneDuration:            monitoring.PruneDuration.NewHistogram(),
+		BlockstoreBase:           monitoring.BlockstoreBase.NewGauge(),
+		NodeRestarts:             monitoring.NodeRestarts.NewCounter(),
+		NodeExits:                monitoring.NodeExits.NewCounterVec(),
+		NodeStall:                monitoring.NodeStall.NewGauge(),
+		NodeStalls:               monitoring.NodeStalls.NewCounterVec(),
+		ProxyRequests:            monitoring.ProxyRequests.NewCounterVec(),
+		ProxyRequestDuration:     monitoring.ProxyRequestDuration.NewHistogramVec(),
+		ProxyRetries:             monitoring.ProxyRetries.NewCounterVec(),
 	}
 	reg.MustRegister(m.PoolHeight, m.NodeHeight, m.MaxHeight, m.MinHeight, m.DataDirSize)
+	reg.MustRegister(m.PoolOutageDuration, m.PoolStatus, m.PoolEventsConnected, m.PoolEndpointHeight, m.PoolEndpointLatency, m.PoolEndpointOutlier)
+	reg.MustRegister(m.PoolVelocity, m.NodeVelocity, m.CatchUpTime)
+	reg.MustRegister(m.GhostModeLeak, m.GhostModeLeaks, m.GhostIsolationUnverified)
+	reg.MustRegister(m.PoolEndpointErrors, m.CurrentMode, m.ModeSeconds, m.ModeTransitions, m.SwitchDowntime)
+	reg.MustRegister(m.PruneRuns, m.PrunedBlocks, m.PruneDuration, m.BlockstoreBase, m.NodeRestarts, m.NodeExits)
+	reg.MustRegister(m.NodeStall, m.NodeStalls)
+	reg.MustRegister(m.ProxyRequests, m.ProxyRequestDuration, m.ProxyRetries)
 	return m
 }
 
-func StartMetricsServer(reg *prometheus.Registry, port int) error {
+func StartMetricsServer(reg *prometheus.Registry, port int, health http.Handler) error {
 	// Create metrics endpoint
 	promHandler := promhttp.HandlerFor(reg, promhttp.HandlerOpts{})
 	http.Handle("/metrics", promHandler)
+
+	// Create health endpoints
+	if health != nil {
+		http.Handle("/healthz", health)
+		http.Handle("/readyz", health)
+		http.Handle("/can-serve", health)
+	}
 	err := http.ListenAndServe(fmt.Sprintf(":%v", port), nil)
 	if err != nil {
 		return err
@@ -5,9 +5,10 @@ import (
 	"fmt"
 	"os"
 
-	"golang.org/x/exp/slices"
-
 	"github.com/KYVENetwork/supervysor/cmd/supervysor/helpers"
+	"github.com/KYVENetwork/supervysor/executor"
+	"github.com/KYVENetwork/supervysor/logging"
+	"github.com/KYVENetwork/supervysor/pool"
 	"github.com/KYVENetwork/supervysor/types"
 
 	"github.com/KYVENetwork/supervysor/settings"
@@ -18,16 +19,17 @@ import (
 )
 
 var (
-	abciEndpoint      string
-	binary            string
-	chainId           string
-	fallbackEndpoints string
-	home              string
-	metrics           bool
-	metricsPort       int
-	poolId            int
-	seeds             string
-	pruningInterval   int
+	abciEndpoint       string
+	binary             string
+	chainId            string
+	fallbackEndpoints  string
+	home               string
+	metrics            bool
+	metricsPort        int
+	poolEventsEndpoint string
+	poolId             int
+	seeds              string
+	pruningInterval    int
 
 	cfg types.SupervysorConfig
 )
@@ -55,7 +57,9 @@ func init() {
 
 	initCmd.Flags().StringVar(&chainId, "chain-id", "kyve-1", "KYVE chain-id")
 
-	initCmd.Flags().StringVar(&fallbackEndpoints, "fallback-endpoints", "", "additional endpoints to query KYVE pool height")
+	initCmd.Flags().StringVar(&fallbackEndpoints, "fallback-endpoints", "", "additional endpoints to query KYVE pool height, either REST (https://) or gRPC (grpc://, grpcs://) (required for chain-ids which are not supported by default)")
+
+	initCmd.Flags().StringVar(&poolEventsEndpoint, "pool-events-endpoint", "", "Tendermint RPC endpoint of the KYVE chain to subscribe to finalized bundles (optional, e.g. https://rpc.kyve.network)")
 
 	initCmd.Flags().IntVar(&pruningInterval, "pruning-interval", 24, "block-pruning interval (hours)")
 
@@ -70,9 +74,9 @@ var initCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Initialize supervysor",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		supportedChains := []string{"kyve-1", "kaon-1", "korellia", "korellia-2"}
-		if !slices.Contains(supportedChains, chainId) {
-			logger.Error("specified chain-id is not supported", "chain-id", chainId)
+		endpoints, err := pool.GetEndpoints(chainId, nil, fallbackEndpoints)
+		if err != nil {
+			logger.Error("specified chain-id is not supported", "chain-id", chainId, "err", err)
 			return fmt.Errorf("not supported chain-id")
 		}
 
@@ -85,7 +89,7 @@ var initCmd = &cobra.Command{
 			logger.Error("pruning-interval should be higher than 6 hours")
 		}
 
-		if err := settings.InitializeSettings(binary, home, poolId, false, seeds, chainId, fallbackEndpoints); err != nil {
+		if err := settings.InitializeSettings(binary, home, false, seeds, pool.NewClient(logger, endpoints, &types.SupervysorConfig{PoolId: poolId}), logger); err != nil {
 			logger.Error("could not initialize settings", "err", err)
 			return err
 		}
@@ -110,20 +114,47 @@ var initCmd = &cobra.Command{
 			logger.Info("initializing supverysor...")
 
 			config := types.SupervysorConfig{
-				ABCIEndpoint:        abciEndpoint,
-				BinaryPath:          binary,
-				ChainId:             chainId,
-				FallbackEndpoints:   fallbackEndpoints,
-				HeightDifferenceMax: settings.Settings.MaxDifference,
-				HeightDifferenceMin: settings.Settings.MaxDifference / 2,
-				HomePath:            home,
-				Interval:            10,
-				Metrics:             metrics,
-				MetricsPort:         metricsPort,
-				PoolId:              poolId,
-				PruningInterval:     pruningInterval,
-				Seeds:               seeds,
-				StateRequests:       false,
+				ABCIEndpoint:              abciEndpoint,
+				BinaryPath:                binary,
+				ChainId:                   chainId,
+				FallbackEndpoints:         fallbackEndpoints,
+				GhostVerifyWindow:         60,
+				HeightDifferenceMax:       settings.Settings.MaxDifference,
+				HeightDifferenceMin:       settings.Settings.MaxDifference / 2,
+				HomePath:                  home,
+				Interval:                  10,
+				LogFormat:                 LogFormatConsole,
+				LogLevel:                  defaultLogLevel,
+				LogMaxAge:                 logging.DefaultMaxAge,
+				LogMaxBackups:             logging.DefaultMaxBackups,
+				LogMaxSize:                logging.DefaultMaxSize,
+				Metrics:                   metrics,
+				MetricsPort:               metricsPort,
+				MinDwellTime:              300,
+				NodeLogs:                  true,
+				NodeLogsModeTag:           false,
+				NotificationBehindDelay:   1800,
+				NotificationDedupWindow:   3600,
+				NotificationDiskThreshold: 0,
+				NotificationRateLimit:     30,
+				PoolEventsEndpoint:        poolEventsEndpoint,
+				PoolHeightMode:            pool.HeightModeFirst,
+				PoolHeightQuorum:          1,
+				PoolHeightTolerance:       0,
+				PoolId:                    poolId,
+				PoolOutageGrace:           300,
+				PoolOutageMax:             0,
+				PoolRequestRetries:        3,
+				PoolRequestTimeout:        10,
+				PredictiveMargin:          60,
+				PredictiveSwitching:       false,
+				PruningInterval:           pruningInterval,
+				Seeds:                     seeds,
+				ShutdownTimeout:           60,
+				StallPolicy:               string(executor.StallPolicyAlert),
+				StallStopWindow:           0,
+				StallWindow:               900,
+				StateRequests:             false,
 			}
 			b, err := toml.Marshal(config)
 			if err != nil {
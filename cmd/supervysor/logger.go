@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"cosmossdk.io/log"
+	"github.com/rs/zerolog"
+)
+
+const (
+	LogFormatConsole = "console"
+	LogFormatJSON    = "json"
+
+	defaultLogLevel = "info"
+)
+
+var (
+	logLevel  string
+	logFormat string
+
+	// logFile receives all logs as JSON independent of the configured format.
+	logFile io.Writer = io.Discard
+)
+
+// setupLogger replaces the logger with one using the given level and format. Empty values are taken
+// from the config if the supervysor was already initialized and fall back to the defaults otherwise.
+func setupLogger(level, format string) error {
+	if config, err := getSupervysorConfig(); err == nil {
+		if level == "" {
+			level = config.LogLevel
+		}
+		if format == "" {
+			format = config.LogFormat
+		}
+	}
+	if level == "" {
+		level = defaultLogLevel
+	}
+	if format == "" {
+		format = LogFormatConsole
+	}
+
+	lvl, err := zerolog.ParseLevel(strings.ToLower(level))
+	if err != nil {
+		return fmt.Errorf("invalid log level %s: %s", level, err)
+	}
+
+	var output io.Writer
+	switch format {
+	case LogFormatConsole:
+		consoleWriter := zerolog.ConsoleWriter{Out: os.Stdout}
+		consoleWriter.FormatCaller = func(i interface{}) string {
+			return "\x1b[36m[supervysor]\x1b[0m"
+		}
+		output = consoleWriter
+	case LogFormatJSON:
+		output = os.Stdout
+	default:
+		return fmt.Errorf("invalid log format %s: must be %s or %s", format, LogFormatConsole, LogFormatJSON)
+	}
+
+	logger = log.NewCustomLogger(zerolog.New(io.MultiWriter(output, logFile)).Level(lvl).With().Timestamp().Logger())
+	return nil
+}
+
+// extractLogFlags removes the global log flags from the arguments of commands which don't parse their
+// flags, since these arguments are passed to the node.
+func extractLogFlags(args []string) (rest []string, level string, format string) {
+	for i := 0; i < len(args); i++ {
+		var value *string
+		name, inline, hasInline := strings.Cut(args[i], "=")
+		switch name {
+		case "--log-level":
+			value = &level
+		case "--log-format":
+			value = &format
+		default:
+			rest = append(rest, args[i])
+			continue
+		}
+
+		if hasInline {
+			*value = inline
+		} else if i+1 < len(args) {
+			i++
+			*value = args[i]
+		}
+	}
+	return rest, level, format
+}
@@ -1,16 +1,14 @@
 package main
 
 import (
-	"io"
 	"os"
 	"path/filepath"
-	"time"
 
 	"cosmossdk.io/log"
-	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
 
 	"github.com/KYVENetwork/supervysor/cmd/supervysor/helpers"
+	"github.com/KYVENetwork/supervysor/logging"
 )
 
 var logger = log.NewLogger(os.Stdout)
@@ -21,6 +19,12 @@ var supervysor = &cobra.Command{
 	Use:     "supervysor",
 	Short:   "Supervysor helps sync a Tendermint node used as a KYVE data source.",
 	Version: Version,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if logLevel == "" && logFormat == "" {
+			return nil
+		}
+		return setupLogger(logLevel, logFormat)
+	},
 }
 
 // main initializes logger including file logging and all supervysor commands.
@@ -29,28 +33,48 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
-	logFilePath := filepath.Join(logsDir, time.Now().Format("20060102_150405")+".log")
 
-	file, err := os.OpenFile(logFilePath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o777)
+	// The log retention is taken from the config if the supervysor was already initialized.
+	maxSize, maxBackups, maxAge := logging.DefaultMaxSize, logging.DefaultMaxBackups, logging.DefaultMaxAge
+	if config, err := getSupervysorConfig(); err == nil {
+		maxSize, maxBackups, maxAge = config.LogMaxSize, config.LogMaxBackups, config.LogMaxAge
+	}
+
+	file, err := logging.NewRotatingWriter(filepath.Join(logsDir, "supervysor.log"), maxSize, maxBackups, maxAge)
 	if err != nil {
 		panic(err)
 	}
 
-	writer := io.MultiWriter(os.Stdout)
-	customConsoleWriter := zerolog.ConsoleWriter{Out: writer}
-	customConsoleWriter.FormatCaller = func(i interface{}) string {
-		return "\x1b[36m[supervysor]\x1b[0m"
+	logFile = file
+	if err = setupLogger("", ""); err != nil {
+		// Invalid log settings in the config are reported by the logger with the default settings.
+		_ = setupLogger(defaultLogLevel, LogFormatConsole)
+		logger.Error("could not configure logger", "err", err)
+	}
+
+	// Remove log files of older versions, which created a new file for every invocation.
+	if err = logging.Prune(logsDir, "", "20060102_150405", ".log", file.MaxBackups, file.MaxAge); err != nil {
+		logger.Error("could not prune old log files", "err", err)
 	}
-	multiLogger := io.MultiWriter(customConsoleWriter, file)
-	logger = log.NewCustomLogger(zerolog.New(multiLogger).With().Timestamp().Logger())
+
+	supervysor.PersistentFlags().StringVar(&logLevel, "log-level", "", "log level (trace, debug, info, warn or error), overrides LogLevel of the config")
+	supervysor.PersistentFlags().StringVar(&logFormat, "log-format", "", "log format (console or json), overrides LogFormat of the config")
 
 	supervysor.AddCommand(initCmd)
 	supervysor.AddCommand(startCmd)
 	supervysor.AddCommand(versionCmd)
 	supervysor.AddCommand(pruneCmd)
 	supervysor.AddCommand(backupCmd)
+	supervysor.AddCommand(serviceCmd)
+	supervysor.AddCommand(metricsCmd)
+
+	err = supervysor.Execute()
 
-	if err = supervysor.Execute(); err != nil {
+	// Flush the log file before exiting, since os.Exit doesn't run deferred functions.
+	_ = file.Sync()
+	_ = file.Close()
+
+	if err != nil {
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/KYVENetwork/supervysor/monitoring"
+)
+
+var (
+	dashboardTitle string
+	diskSize       float64
+	exportPath     string
+	outageGrace    int
+)
+
+func init() {
+	exportDashboardCmd.Flags().StringVar(&exportPath, "output", "-", "path of the generated dashboard (use '-' to print it)")
+
+	exportDashboardCmd.Flags().StringVar(&dashboardTitle, "title", "Supervysor", "title of the dashboard")
+
+	exportRulesCmd.Flags().StringVar(&exportPath, "output", "-", "path of the generated rule file (use '-' to print it)")
+
+	exportRulesCmd.Flags().Float64Var(&diskSize, "disk-size", 1000, "size of the disk of the data directory in GB, alerting at 90%")
+
+	exportRulesCmd.Flags().IntVar(&outageGrace, "outage-grace", 0, "seconds the KYVE API can be unreachable without alerting (default PoolOutageGrace of the config or 300)")
+
+	metricsCmd.AddCommand(exportDashboardCmd)
+	metricsCmd.AddCommand(exportRulesCmd)
+}
+
+var metricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Export monitoring resources for the supervysor metrics",
+}
+
+var exportDashboardCmd = &cobra.Command{
+	Use:   "export-dashboard",
+	Short: "Generate a Grafana dashboard for the supervysor metrics",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		definitions := monitoring.Definitions()
+
+		dashboard, err := monitoring.Dashboard(definitions, dashboardTitle)
+		if err != nil {
+			logger.Error("could not generate dashboard", "err", err)
+			return err
+		}
+
+		return export(dashboard)
+	},
+}
+
+var exportRulesCmd = &cobra.Command{
+	Use:   "export-rules",
+	Short: "Generate Prometheus alerting rules for the supervysor metrics",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		definitions := monitoring.Definitions()
+
+		if outageGrace <= 0 {
+			outageGrace = 300
+			if config, err := getSupervysorConfig(); err == nil && config.PoolOutageGrace > 0 {
+				outageGrace = config.PoolOutageGrace
+			}
+		}
+
+		rules, err := monitoring.Rules(definitions, monitoring.RuleOptions{
+			DiskSize:    diskSize * 1e9,
+			OutageGrace: outageGrace,
+		})
+		if err != nil {
+			logger.Error("could not generate alerting rules", "err", err)
+			return err
+		}
+
+		return export(rules)
+	},
+}
+
+// export prints the generated content or writes it to the output path.
+func export(content []byte) error {
+	if exportPath == "-" {
+		fmt.Println(string(content))
+		return nil
+	}
+
+	if err := os.WriteFile(exportPath, content, 0o644); err != nil {
+		logger.Error("could not write file", "err", err)
+		return err
+	}
+	logger.Info("file written", "path", exportPath)
+	return nil
+}
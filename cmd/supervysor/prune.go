@@ -25,7 +25,7 @@ var pruneCmd = &cobra.Command{
 	Use:   "prune-blocks",
 	Short: "Prune blocks until a specific height",
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := store.PruneBlocks(home, untilHeight, logger); err != nil {
+		if _, _, err := store.PruneBlocks(home, untilHeight, logger); err != nil {
 			logger.Error(err.Error())
 		}
 	},
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+
+	"github.com/KYVENetwork/supervysor/hooks"
+	"github.com/KYVENetwork/supervysor/node"
+	"github.com/KYVENetwork/supervysor/systemd"
+	"github.com/spf13/cobra"
+)
+
+var (
+	daemonName     string
+	environment    []string
+	serviceUser    string
+	unitPath       string
+	watchdogPeriod int
+)
+
+func init() {
+	serviceInstallCmd.Flags().StringVar(&unitPath, "path", "/etc/systemd/system/supervysor.service", "path of the generated unit file (use '-' to print it)")
+
+	serviceInstallCmd.Flags().StringVar(&serviceUser, "user", "", "user running the service (default current user)")
+
+	serviceInstallCmd.Flags().StringVar(&daemonName, "daemon-name", "", "name of the chain binary run by cosmovisor (e.g. osmosisd)")
+
+	serviceInstallCmd.Flags().StringArrayVar(&environment, "env", nil, "additional environment variables of the service (e.g. --env KEY=VALUE)")
+
+	serviceInstallCmd.Flags().IntVar(&watchdogPeriod, "watchdog", 600, "seconds without progress of the supervysor until systemd restarts it (set 0 to disable)")
+
+	serviceCmd.AddCommand(serviceInstallCmd)
+}
+
+var serviceCmd = &cobra.Command{
+	Use:   "service",
+	Short: "Manage the systemd service of the supervysor",
+}
+
+// The serviceInstallCmd generates a systemd unit file from the current config, which runs the
+// supervysor as a service of Type=notify with watchdog.
+var serviceInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Generate a systemd unit file from the current config",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := getSupervysorConfig()
+		if err != nil {
+			logger.Error("could not load config", "err", err)
+			return err
+		}
+
+		executable, err := os.Executable()
+		if err != nil {
+			logger.Error("could not resolve supervysor executable", "err", err)
+			return err
+		}
+		if executable, err = filepath.EvalSymlinks(executable); err != nil {
+			logger.Error("could not resolve supervysor executable", "err", err)
+			return err
+		}
+
+		if serviceUser == "" {
+			current, err := user.Current()
+			if err != nil {
+				logger.Error("could not get current user", "err", err)
+				return err
+			}
+			serviceUser = current.Username
+		}
+
+		var env []string
+		if strings.HasSuffix(config.BinaryPath, "/cosmovisor") {
+			env = append(env, "DAEMON_HOME="+config.HomePath)
+			if daemonName != "" {
+				env = append(env, "DAEMON_NAME="+daemonName)
+			} else {
+				logger.Error("cosmovisor requires DAEMON_NAME, please add it with --daemon-name")
+			}
+		}
+		env = append(env, environment...)
+
+		// The supervysor runs the stop hooks and shuts the node down before it exits, the stop timeout needs
+		// to exceed the worst case of both, otherwise systemd kills the node while it's shut down.
+		stopDuration := node.MaxShutdownDuration(config) + hooks.MaxDuration(config.Hooks, hooks.EventStop)
+
+		unit := systemd.Unit{
+			Description:    fmt.Sprintf("Supervysor for KYVE pool %d", config.PoolId),
+			User:           serviceUser,
+			ExecStart:      executable + " start",
+			Environment:    env,
+			WatchdogSec:    watchdogPeriod,
+			TimeoutStopSec: int(stopDuration.Seconds()) + 30,
+			RestartSec:     10,
+			LimitNOFILE:    65535,
+		}
+
+		content, err := unit.Render()
+		if err != nil {
+			logger.Error("could not generate unit file", "err", err)
+			return err
+		}
+
+		if unitPath == "-" {
+			fmt.Print(string(content))
+			return nil
+		}
+
+		if err = os.WriteFile(unitPath, content, 0o644); err != nil {
+			logger.Error("could not write unit file", "err", err)
+			return err
+		}
+
+		logger.Info("unit file written, enable the service with 'systemctl daemon-reload && systemctl enable --now "+strings.TrimSuffix(filepath.Base(unitPath), ".service")+"'", "path", unitPath)
+		return nil
+	},
+}
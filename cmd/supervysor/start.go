@@ -1,8 +1,14 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math"
+	"os/signal"
 	"path/filepath"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/KYVENetwork/supervysor/cmd/supervysor/helpers"
@@ -11,9 +17,20 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/KYVENetwork/supervysor/executor"
+	"github.com/KYVENetwork/supervysor/hooks"
+	"github.com/KYVENetwork/supervysor/logging"
+	"github.com/KYVENetwork/supervysor/node"
+	"github.com/KYVENetwork/supervysor/notification"
 	"github.com/KYVENetwork/supervysor/pool"
+	"github.com/KYVENetwork/supervysor/proxy"
+	"github.com/KYVENetwork/supervysor/store"
+	"github.com/KYVENetwork/supervysor/systemd"
 )
 
+// defaultBehindNotificationDelay is how long the node needs to be behind the pool until it's notified, if
+// NotificationBehindDelay isn't configured.
+const defaultBehindNotificationDelay = 30 * time.Minute
+
 // The startCmd of the supervysor launches and manages the node process using the specified binary.
 // It periodically retrieves the heights of the node and the associated KYVE pool, and dynamically adjusts
 // the sync mode of the node based on these heights.
@@ -22,45 +39,198 @@ var startCmd = &cobra.Command{
 	Short:              "Start a supervysed Tendermint node",
 	DisableFlagParsing: true,
 	RunE: func(cmd *cobra.Command, flags []string) error {
+		// Flags aren't parsed, so the global log flags need to be removed before they are passed to the node.
+		flags, level, format := extractLogFlags(flags)
+		if level != "" || format != "" {
+			if err := setupLogger(level, format); err != nil {
+				logger.Error("could not configure logger", "err", err)
+				return err
+			}
+		}
+
 		// Load initialized config.
 		config, err := getSupervysorConfig()
 		if err != nil {
 			logger.Error("could not load config", "err", err)
 			return err
 		}
+		logger = logger.With("pool_id", config.PoolId)
 		metrics := config.Metrics
 
+		endpoints, err := pool.GetEndpoints(config.ChainId, config.Chains, config.FallbackEndpoints)
+		if err != nil {
+			logger.Error("could not resolve KYVE endpoints", "err", err)
+			return err
+		}
+		poolClient := pool.NewClient(logger, endpoints, config)
+		defer poolClient.Close()
+
 		// Create Prometheus registry
 		reg := prometheus.NewRegistry()
 		m := helpers.NewMetrics(reg)
 
+		// Stop the supervision loop on SIGINT and SIGTERM and shut the node down gracefully.
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+
+		e := executor.NewExecutor(&logger, config)
+
 		if metrics {
+			poolClient.Metrics = m
+			e.Metrics = m
+
 			go func() {
-				err := helpers.StartMetricsServer(reg, config.MetricsPort)
+				err := helpers.StartMetricsServer(reg, config.MetricsPort, e.HealthHandler())
 				if err != nil {
 					panic(err)
 				}
 			}()
 		}
 
-		e := executor.NewExecutor(&logger, config)
+		notifications, err := notification.NewNotifier(logger, config)
+		if err != nil {
+			logger.Error("invalid notifiers", "err", err)
+			return err
+		}
+		defer notifications.Wait()
+		e.Notifier = notifications
+
+		if e.Hooks, err = hooks.NewRunner(logger, config); err != nil {
+			logger.Error("invalid hooks", "err", err)
+			return err
+		}
+
+		// Optionally serve the endpoints of the node through proxies, which hold requests during restarts.
+		proxies, err := proxy.NewProxies(logger, config, e.Health)
+		if err != nil {
+			logger.Error("invalid proxies", "err", err)
+			return err
+		}
+		for _, p := range proxies {
+			if metrics {
+				p.Metrics = m
+			}
+			go func(p *proxy.Proxy) {
+				if err := p.ListenAndServe(ctx); err != nil {
+					logger.Error("proxy stopped", "proxy", p.Name, "err", err)
+				}
+			}(p)
+		}
+
+		// Report readiness, status and watchdog pings to systemd if started as a service of Type=notify.
+		notifier := systemd.NewNotifier()
+		notify := func(err error) {
+			if err != nil {
+				logger.Error("could not notify systemd", "err", err)
+			}
+		}
+		ready := false
+
+		// Capture the node output into its own rotated log files instead of the supervysor output.
+		if config.NodeLogs {
+			logsDir, err := helpers.GetLogsDir()
+			if err != nil {
+				logger.Error("could not get logs directory", "err", err)
+				return err
+			}
+
+			nodeLog, err := logging.NewRotatingWriter(filepath.Join(logsDir, "node", "node.log"), config.LogMaxSize, config.LogMaxBackups, config.LogMaxAge)
+			if err != nil {
+				logger.Error("could not open node log file", "err", err)
+				return err
+			}
+			defer nodeLog.Close()
+
+			e.Process.Output = nodeLog
+			logger.Info("capturing node output", "path", nodeLog.Path)
+		}
+
+		if err := executor.ValidateStatusPolicies(config.PoolStatusPolicies); err != nil {
+			logger.Error("invalid pool status policies", "err", err)
+			return err
+		}
+		if err := executor.ValidateStallPolicy(config.StallPolicy); err != nil {
+			logger.Error("invalid stall policy", "err", err)
+			return err
+		}
+
+		// The blockstore can only be read before the node is started, it's updated after every pruning.
+		if base, err := store.GetBaseHeight(config.HomePath); err != nil {
+			logger.Error("could not get blockstore base height", "err", err)
+		} else {
+			e.SetBaseHeight(base)
+		}
+
+		// stopNode stops the node before the supervysor exits with the given error, so the node never keeps
+		// running without being supervised.
+		stopNode := func(err error) error {
+			if stopErr := e.Stop(); stopErr != nil {
+				logger.Error("could not stop node", "err", stopErr)
+			}
+			return err
+		}
 
 		// Start data source node initially.
 		if err := e.InitialStart(flags); err != nil {
 			logger.Error("initial start failed", "err", err)
-			return err
+			return stopNode(err)
 		}
 
-		currentMode := "normal"
+		currentMode := executor.ModeNormal
+		lastSwitch := time.Now()
+		lastPrune := time.Now()
+		lastModeUpdate := time.Now()
+		var behindSince time.Time
+		behindNotificationDelay := defaultBehindNotificationDelay
+		if config.NotificationBehindDelay > 0 {
+			behindNotificationDelay = time.Duration(config.NotificationBehindDelay) * time.Second
+		}
+
+		// setMode records a switch to the given mode after it was enabled successfully.
+		setMode := func(mode string, nodeHeight, poolHeight int) {
+			notifications.Notify(notification.Event{
+				Type:       notification.EventModeChange,
+				Severity:   notification.SeverityInfo,
+				Message:    fmt.Sprintf("switched from %s to %s mode", currentMode, mode),
+				Mode:       mode,
+				NodeHeight: nodeHeight,
+				PoolHeight: poolHeight,
+			})
 
+			if metrics {
+				m.ModeSeconds.WithLabelValues(currentMode).Add(time.Since(lastModeUpdate).Seconds())
+				m.ModeTransitions.WithLabelValues(currentMode, mode).Inc()
+				m.CurrentMode.Reset()
+				m.CurrentMode.WithLabelValues(mode).Set(1)
+			}
+			currentMode = mode
+			lastSwitch = time.Now()
+			lastModeUpdate = lastSwitch
+		}
 		if metrics {
+			m.CurrentMode.WithLabelValues(currentMode).Set(1)
+		}
+
+		// Measure the data directory size, which is exposed as metric, used for notifications and by the policy.
+		var diskUsage atomic.Uint64
+		if metrics || config.NotificationDiskThreshold > 0 {
 			go func() {
 				for {
 					dbSize, err := helpers.GetDirectorySize(filepath.Join(config.HomePath, "data"))
 					if err != nil {
-						logger.Error("could not get data directory size; will not expose metrics", "err", err)
+						logger.Error("could not get data directory size", "err", err)
 					} else {
-						m.DataDirSize.Set(dbSize)
+						diskUsage.Store(math.Float64bits(dbSize))
+						if metrics {
+							m.DataDirSize.Set(dbSize)
+						}
+						if config.NotificationDiskThreshold > 0 && dbSize > float64(config.NotificationDiskThreshold)*1e9 {
+							notifications.Notify(notification.Event{
+								Type:     notification.EventDiskThreshold,
+								Severity: notification.SeverityWarning,
+								Message:  fmt.Sprintf("data directory exceeds %d GB", config.NotificationDiskThreshold),
+							})
+						}
 					}
 
 					time.Sleep(time.Second * time.Duration(120))
@@ -68,116 +238,397 @@ var startCmd = &cobra.Command{
 			}()
 		}
 
-		var pruningCount float64 = 0
+		// Optionally subscribe to finalized bundles to update the pool height immediately.
+		var poolUpdates <-chan struct{}
+		var subscriber *pool.EventSubscriber
+		if config.PoolEventsEndpoint != "" {
+			subscriber = pool.NewEventSubscriber(logger, config.PoolEventsEndpoint, config.PoolId)
+			poolUpdates = subscriber.Updates()
+
+			go subscriber.Run(ctx)
+		}
+
+		// Optionally predict when the pool catches up to switch back to Normal Mode early enough.
+		var predictor *executor.Predictor
+		if config.PredictiveSwitching {
+			predictor = executor.NewPredictor(config.PredictiveMargin)
+		}
+
+		policy := e.NewPolicy(predictor)
+
+		poolStatus := ""
+		outage := pool.NewOutageTracker(config.PoolOutageGrace, config.PoolOutageMax)
+		stall := executor.NewStallDetector(config.StallWindow, config.StallStopWindow)
+		lastNodeHeight := 0
+
+		// observeStall registers the height of the node with the stall detector and applies the stall policy.
+		// An error is returned if the supervysor has to stop, the stall was already notified then.
+		observeStall := func(nodeHeight int) error {
+			switch stall.Observe(nodeHeight, time.Now()) {
+			case executor.StallDetected:
+				duration := stall.Duration(time.Now()).Round(time.Second)
+				notifications.Notify(notification.Event{
+					Type:       notification.EventNodeStalled,
+					Severity:   notification.SeverityWarning,
+					Message:    fmt.Sprintf("node height did not advance for %s, applying %s stall policy", duration, e.StallPolicy()),
+					Mode:       currentMode,
+					NodeHeight: nodeHeight,
+				})
+				logger.Error("node height did not advance, node is stalled", "mode", currentMode, "node_height", nodeHeight, "duration", duration.String(), "policy", e.StallPolicy())
+
+				stopKeepAlive := notifier.KeepAlive()
+				err := e.RecoverStall(flags)
+				stopKeepAlive()
+				if err != nil {
+					logger.Error("could not recover stalled node", "err", err)
+					return err
+				}
+				if e.StopOnStall() {
+					return fmt.Errorf("node height did not advance for %s", duration)
+				}
+			case executor.StallEscalate:
+				duration := stall.Duration(time.Now()).Round(time.Second)
+				notifications.Notify(notification.Event{
+					Type:       notification.EventNodeStalled,
+					Severity:   notification.SeverityCritical,
+					Message:    fmt.Sprintf("node height did not advance for %s, shutting down", duration),
+					Mode:       currentMode,
+					NodeHeight: nodeHeight,
+					Key:        "shutdown",
+				})
+				logger.Error("node stalled longer than stop window, shutting down", "mode", currentMode, "node_height", nodeHeight, "duration", duration.String())
+				return fmt.Errorf("node height did not advance for %s", duration)
+			}
+			if metrics {
+				m.NodeStall.Set(stall.Duration(time.Now()).Seconds())
+			}
+			return nil
+		}
+
 		for {
+			if ctx.Err() != nil {
+				logger.Info("received shutdown signal, stopping supervysor", "mode", currentMode)
+				notify(notifier.Stopping("stopping node"))
+				if err := e.Stop(); err != nil {
+					logger.Error("could not stop node gracefully", "err", err)
+					return err
+				}
+				logger.Info("supervysor stopped gracefully")
+				return nil
+			}
+
+			notify(notifier.Ping())
+
+			if metrics {
+				m.ModeSeconds.WithLabelValues(currentMode).Add(time.Since(lastModeUpdate).Seconds())
+				lastModeUpdate = time.Now()
+			}
+
 			// Request data source node height and KYVE pool height to calculate difference.
-			nodeHeight, err := e.GetHeight()
+			// The status query is retried with backoff while the node is starting, which can exceed the watchdog interval.
+			stopKeepAlive := notifier.KeepAlive()
+			status, err := e.GetStatus(ctx)
+			stopKeepAlive()
 			if err != nil {
-				logger.Error("could not get node height", "err", err)
-				if shutdownErr := e.Shutdown(); shutdownErr != nil {
-					logger.Error("could not shutdown node process", "err", shutdownErr)
+				if ctx.Err() != nil {
+					continue
 				}
-				return err
+				switch {
+				case errors.Is(err, node.ErrNodeStarting) && e.Health().Running:
+					// The node process is running, but its RPC isn't up yet, e.g. while it replays blocks. The
+					// wait counts towards the stall window in both modes, so a node hanging in its startup
+					// is handled like a stalled node.
+					logger.Info("node RPC is not reachable yet, waiting for node to start", "mode", currentMode, "err", err)
+					if err := observeStall(lastNodeHeight); err != nil {
+						return stopNode(err)
+					}
+					continue
+				case errors.Is(err, node.ErrNodeStarting):
+					logger.Error("node RPC is not reachable, node is not running", "mode", currentMode, "err", err)
+				case errors.Is(err, node.ErrNodeUnresponsive):
+					logger.Error("node RPC is not responding, restarting hung node", "mode", currentMode, "err", err)
+
+					stopKeepAlive := notifier.KeepAlive()
+					err = e.Restart(flags)
+					stopKeepAlive()
+					if err == nil {
+						stall.Reset()
+						continue
+					}
+					logger.Error("could not restart node", "err", err)
+				default:
+					logger.Error("could not get node height", "mode", currentMode, "err", err)
+				}
+				return stopNode(err)
 			}
+			nodeHeight := int(status.LatestHeight)
+			lastNodeHeight = nodeHeight
 			if metrics {
 				m.NodeHeight.Set(float64(nodeHeight))
 			}
+			if !ready {
+				notify(notifier.Ready(fmt.Sprintf("node running in %s mode at height %d", currentMode, nodeHeight)))
+				ready = true
+			}
 
-			poolHeight, err := pool.GetPoolHeight(config.ChainId, config.PoolId, config.FallbackEndpoints)
+			// Detect a node which stopped syncing in Normal Mode, in Ghost Mode its height is expected to stay flat.
+			if currentMode == executor.ModeNormal {
+				if err := observeStall(nodeHeight); err != nil {
+					return stopNode(err)
+				}
+			} else {
+				stall.Reset()
+			}
+			if metrics {
+				m.NodeStall.Set(stall.Duration(time.Now()).Seconds())
+			}
+
+			poolHeights, err := poolClient.GetPoolHeights(ctx)
 			if err != nil {
-				logger.Error("could not get pool height", "err", err)
-				if shutdownErr := e.Shutdown(); shutdownErr != nil {
-					logger.Error("could not shutdown node process", "err", shutdownErr)
+				if ctx.Err() != nil {
+					continue
 				}
-				return err
+
+				// Keep the node running in its current mode and skip pruning while the KYVE API is unreachable.
+				switch outage.Failure(time.Now()) {
+				case pool.OutageEscalate:
+					notifications.Notify(notification.Event{
+						Type:       notification.EventPoolOutage,
+						Severity:   notification.SeverityCritical,
+						Message:    "KYVE API outage exceeded maximum duration, shutting down",
+						Mode:       currentMode,
+						NodeHeight: nodeHeight,
+						Key:        "shutdown",
+					})
+					logger.Error("KYVE API outage exceeded maximum duration, shutting down", "mode", currentMode, "node_height", nodeHeight, "duration", outage.Duration(time.Now()).String(), "err", err)
+					return stopNode(err)
+				case pool.OutageAlert:
+					notifications.Notify(notification.Event{
+						Type:       notification.EventPoolOutage,
+						Severity:   notification.SeverityCritical,
+						Message:    "KYVE API unreachable beyond grace period, keeping current mode",
+						Mode:       currentMode,
+						NodeHeight: nodeHeight,
+					})
+					logger.Error("KYVE API unreachable beyond grace period, keeping current mode", "mode", currentMode, "node_height", nodeHeight, "duration", outage.Duration(time.Now()).String(), "err", err)
+				default:
+					logger.Info("could not get pool height, keeping current mode", "mode", currentMode, "node_height", nodeHeight, "err", err)
+				}
+				if metrics {
+					m.PoolOutageDuration.Set(outage.Duration(time.Now()).Seconds())
+				}
+
+				select {
+				case <-ctx.Done():
+				case <-time.After(outage.Backoff(time.Second * time.Duration(config.Interval))):
+				}
+				continue
+			}
+			if duration, ended := outage.Success(time.Now()); ended {
+				logger.Info("KYVE API reachable again", "outage-duration", duration.String())
 			}
+			if metrics {
+				m.PoolOutageDuration.Set(0)
+			}
+			poolHeight := poolHeights.Height
 			if metrics {
 				m.PoolHeight.Set(float64(poolHeight))
 			}
 
-			logger.Info("fetched heights successfully", "node", nodeHeight, "pool", poolHeight, "max-height", poolHeight+config.HeightDifferenceMax, "min-height", poolHeight+config.HeightDifferenceMin)
+			// All logs of this iteration carry the mode and heights the decision is based on.
+			stepLogger := logger.With("mode", currentMode, "node_height", nodeHeight, "pool_height", poolHeight)
 
-			if config.PruningInterval != 0 {
-				logger.Info("current pruning count", "pruning-count", fmt.Sprintf("%.2f", pruningCount), "pruning-threshold", config.PruningInterval)
-				if pruningCount > float64(config.PruningInterval) && nodeHeight > 0 {
-					if currentMode == "ghost" {
-						pruneHeight := poolHeight
-						if nodeHeight < poolHeight {
-							pruneHeight = nodeHeight
-						}
-						logger.Info("pruning blocks after node shutdown", "until-height", pruneHeight)
+			stepLogger.Info("fetched heights successfully", "max-height", poolHeight+config.HeightDifferenceMax, "min-height", poolHeight+config.HeightDifferenceMin, "catching-up", status.CatchingUp)
 
-						err = e.PruneBlocks(config.HomePath, pruneHeight-1, flags)
-						if err != nil {
-							logger.Error("could not prune blocks", "err", err)
-							return err
-						}
-					} else {
-						if nodeHeight < poolHeight {
-							logger.Info("pruning blocks after node shutdown", "until-height", nodeHeight)
-
-							err = e.PruneBlocks(config.HomePath, nodeHeight-1, flags)
-							if err != nil {
-								logger.Error("could not prune blocks", "err", err)
-								return err
-							}
-						}
+			if predictor != nil {
+				predictor.Observe(nodeHeight, poolHeight, currentMode == executor.ModeGhost, time.Now())
+
+				if metrics {
+					if velocity, ok := predictor.Pool.BlocksPerHour(); ok {
+						m.PoolVelocity.Set(velocity)
+					}
+					if velocity, ok := predictor.Node.BlocksPerHour(); ok {
+						m.NodeVelocity.Set(velocity)
 					}
-					pruningCount = 0
+					m.CatchUpTime.Set(predictor.CatchUpTime.Seconds())
 				}
 			}
 
-			// Calculate height difference to enable the correct mode.
-			heightDiff := nodeHeight - poolHeight
-
+			if poolHeights.Status != poolStatus {
+				stepLogger.Info("pool status changed", "status", poolHeights.Status, "action", e.StatusAction(poolHeights.Status))
+				poolStatus = poolHeights.Status
+			}
 			if metrics {
+				m.PoolStatus.Reset()
+				m.PoolStatus.WithLabelValues(poolStatus).Set(1)
+
 				m.MaxHeight.Set(float64(poolHeight + config.HeightDifferenceMax))
 				m.MinHeight.Set(float64(poolHeight + config.HeightDifferenceMin))
 			}
 
-			if heightDiff >= config.HeightDifferenceMax {
-				if currentMode != "ghost" {
-					logger.Info("enabling GhostMode")
+			if config.PruningInterval != 0 {
+				stepLogger.Info("current pruning count", "pruning-count", fmt.Sprintf("%.2f", time.Since(lastPrune).Hours()), "pruning-threshold", config.PruningInterval)
+			}
+
+			decision := policy.Decide(executor.Observation{
+				Mode:                currentMode,
+				NodeHeight:          nodeHeight,
+				PoolHeight:          poolHeight,
+				PruneHeight:         poolHeights.PruneHeight,
+				PoolStatus:          poolStatus,
+				PoolSettings:        poolHeights.Settings,
+				DiskUsage:           math.Float64frombits(diskUsage.Load()),
+				TimeSinceLastSwitch: time.Since(lastSwitch),
+				TimeSinceLastPrune:  time.Since(lastPrune),
+			})
+
+			e.SetHeights(nodeHeight, poolHeight)
+
+			// Calculate height difference to enable the correct mode.
+			heightDiff := nodeHeight - poolHeight
+
+			// Notify if the node can't be used as data source for a longer time.
+			if heightDiff > 0 {
+				behindSince = time.Time{}
+			} else if behindSince.IsZero() {
+				behindSince = time.Now()
+			} else if time.Since(behindSince) > behindNotificationDelay {
+				notifications.Notify(notification.Event{
+					Type:       notification.EventNodeBehindPool,
+					Severity:   notification.SeverityWarning,
+					Message:    fmt.Sprintf("node is behind the pool for more than %s", behindNotificationDelay),
+					Mode:       currentMode,
+					NodeHeight: nodeHeight,
+					PoolHeight: poolHeight,
+				})
+			}
+
+			// Pruning, mode switches and the Ghost Mode verification restart the node and can exceed the
+			// watchdog interval.
+			stopKeepAlive = notifier.KeepAlive()
+
+			if decision.Prune && decision.PruneHeight > 0 {
+				stepLogger.Info("pruning blocks after node shutdown", "until-height", decision.PruneHeight)
+
+				notify(notifier.Status(fmt.Sprintf("pruning blocks until height %d", decision.PruneHeight)))
+				err = e.PruneBlocks(ctx, config.HomePath, decision.PruneHeight-1, flags)
+				switch {
+				case errors.Is(err, hooks.ErrAborted):
+					stepLogger.Error("pruning aborted by hook, retrying in next interval", "err", err)
+				case ctx.Err() != nil:
+					stopKeepAlive()
+					continue
+				case errors.Is(err, executor.ErrPruningFailed):
+					// The node is running again, pruning is retried after the next pruning interval.
+					stepLogger.Error("could not prune blocks, retrying after pruning interval", "err", err)
+					lastPrune = time.Now()
+					stall.Reset()
+				case err != nil:
+					stepLogger.Error("could not prune blocks", "err", err)
+					return stopNode(err)
+				default:
+					lastPrune = time.Now()
+					stall.Reset()
+					notify(notifier.Status(fmt.Sprintf("pruned blocks until height %d in %s mode", decision.PruneHeight, currentMode)))
+				}
+			} else if decision.Prune {
+				// Nothing is pruned while the node is ahead of the pool in Normal Mode, the interval starts again.
+				lastPrune = time.Now()
+			}
+
+			switch decision.Action {
+			case executor.ActionGhost:
+				if currentMode != executor.ModeGhost {
+					stepLogger.Info("enabling GhostMode", "reason", decision.Reason)
 				} else {
-					logger.Info("keeping GhostMode")
+					stepLogger.Info("keeping GhostMode")
 				}
 				// Data source node has synced far enough, enable or keep Ghost Mode
-				if err = e.EnableGhostMode(flags); err != nil {
-					logger.Error("could not enable Ghost Mode", "err", err)
+				if err = e.EnableGhostMode(ctx, flags); errors.Is(err, hooks.ErrAborted) {
+					stepLogger.Error("enabling Ghost Mode aborted by hook, retrying in next interval", "err", err)
+					break
+				} else if ctx.Err() != nil {
+					stopKeepAlive()
+					continue
+				} else if err != nil {
+					stepLogger.Error("could not enable Ghost Mode", "err", err)
 
-					if shutdownErr := e.Shutdown(); shutdownErr != nil {
-						logger.Error("could not shutdown node process", "err", shutdownErr)
+					return stopNode(err)
+				}
+				if currentMode != executor.ModeGhost {
+					if predictor != nil {
+						predictor.GhostModeEnabled()
+					}
+					setMode(executor.ModeGhost, nodeHeight, poolHeight)
+					notify(notifier.Status(fmt.Sprintf("Ghost Mode enabled at node height %d, pool height %d", nodeHeight, poolHeight)))
+
+					// Verify that the node actually stopped syncing, since disk fills up otherwise.
+					report, err := e.VerifyGhostMode(ctx, flags)
+					if metrics {
+						if report.Leaking {
+							m.GhostModeLeak.Set(1)
+							m.GhostModeLeaks.Inc()
+						} else {
+							m.GhostModeLeak.Set(0)
+						}
+					}
+					if err != nil && ctx.Err() == nil {
+						stepLogger.Error("ghost_mode_leak: could not stop node from syncing in Ghost Mode", "err", err)
+
+						return stopNode(err)
 					}
-					return err
 				}
-				currentMode = "ghost"
-			} else if heightDiff < config.HeightDifferenceMax && heightDiff > config.HeightDifferenceMin {
-				// No threshold reached, keep current mode
-				logger.Info("keeping current Mode", "mode", currentMode, "height-difference", heightDiff)
-			} else {
-				if currentMode != "normal" {
-					logger.Info("enabling NormalMode")
+			case executor.ActionNormal:
+				if currentMode != executor.ModeNormal {
+					stepLogger.Info("enabling NormalMode", "reason", decision.Reason)
 				} else {
-					logger.Info("keeping NormalMode")
+					stepLogger.Info("keeping NormalMode")
 				}
-				// Difference is < HeightDifferenceMin, Data source needs to catch up, enable or keep Normal Mode
-				if err = e.EnableNormalMode(flags); err != nil {
-					logger.Error("could not enable Normal Mode", "err", err)
+				// Data source node needs to catch up, enable or keep Normal Mode
+				if err = e.EnableNormalMode(ctx, flags); errors.Is(err, hooks.ErrAborted) {
+					stepLogger.Error("enabling Normal Mode aborted by hook, retrying in next interval", "err", err)
+					break
+				} else if ctx.Err() != nil {
+					stopKeepAlive()
+					continue
+				} else if err != nil {
+					stepLogger.Error("could not enable Normal Mode", "err", err)
 
-					if shutdownErr := e.Shutdown(); shutdownErr != nil {
-						logger.Error("could not shutdown node process", "err", shutdownErr)
+					return stopNode(err)
+				}
+				if currentMode != executor.ModeNormal {
+					if predictor != nil {
+						predictor.NormalModeEnabled(nodeHeight, time.Now())
 					}
-					return err
+					setMode(executor.ModeNormal, nodeHeight, poolHeight)
+					notify(notifier.Status(fmt.Sprintf("Normal Mode enabled at node height %d, pool height %d", nodeHeight, poolHeight)))
 				}
-				currentMode = "normal"
 
 				// Diff < 0, can't use node as data source
 				if heightDiff <= 0 {
-					logger.Info("node has not reached pool height yet, can not use it as data source")
+					stepLogger.Info("node has not reached pool height yet, can not use it as data source")
 				}
+			default:
+				// No threshold reached, keep current mode
+				stepLogger.Info("keeping current Mode", "height-difference", heightDiff, "reason", decision.Reason)
+			}
+			stopKeepAlive()
+
+			if metrics && subscriber != nil {
+				if subscriber.Connected() {
+					m.PoolEventsConnected.Set(1)
+				} else {
+					m.PoolEventsConnected.Set(0)
+				}
+			}
+
+			// Wait for the next interval or until a bundle of the pool got finalized.
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second * time.Duration(config.Interval)):
+			case <-poolUpdates:
+				logger.Info("bundle finalized, updating pool height")
 			}
-			pruningCount = pruningCount + float64(config.Interval)/60/60
-			time.Sleep(time.Second * time.Duration(config.Interval))
 		}
 	},
 }
@@ -1,29 +1,71 @@
 package executor
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/KYVENetwork/supervysor/store"
 
 	"cosmossdk.io/log"
 
+	"github.com/KYVENetwork/supervysor/hooks"
 	"github.com/KYVENetwork/supervysor/node"
+	"github.com/KYVENetwork/supervysor/node/helpers"
+	"github.com/KYVENetwork/supervysor/notification"
 	"github.com/KYVENetwork/supervysor/types"
 )
 
+// ErrPruningFailed is returned if the blocks couldn't be pruned, the node is running again in its
+// current mode.
+var ErrPruningFailed = errors.New("pruning failed")
+
 type Executor struct {
 	Logger  log.Logger
 	Cfg     *types.SupervysorConfig
 	Process types.ProcessType
+	Peers   *node.PeerManager
+	Metrics *types.Metrics
+	// Notifier receives the supervision events, all events are dropped if it is nil.
+	Notifier *notification.Notifier
+	// Hooks are run before and after the actions, no hooks are run if it is nil.
+	Hooks *hooks.Runner
+
+	// expectedExit is the ID of the process which is shut down by the supervysor, so its exit isn't
+	// reported as crash.
+	expectedExit atomic.Int64
+	// crashedExit is the ID of the last process which exited unexpectedly, its exit is already recorded.
+	crashedExit atomic.Int64
+
+	nodeHeight int
+	poolHeight int
+	// seedList is the index of the seed list the node was restarted with last, 0 are the configured seeds.
+	seedList int
+
+	healthMu sync.RWMutex
+	health   Health
 }
 
 func NewExecutor(logger *log.Logger, cfg *types.SupervysorConfig) *Executor {
-	return &Executor{Logger: *logger, Cfg: cfg, Process: types.ProcessType{Id: -1, GhostMode: false}}
+	return &Executor{
+		Logger:  *logger,
+		Cfg:     cfg,
+		Process: types.ProcessType{Id: -1, GhostMode: false},
+		Peers:   node.NewPeerManager(cfg.ABCIEndpoint),
+	}
 }
 
 // InitialStart initiates the node by starting it in the initial mode.
 func (e *Executor) InitialStart(flags []string) error {
+	if err := e.Hooks.Run(hooks.EventStart, hooks.PhasePre, e.hookEnv()); err != nil {
+		return err
+	}
+
 	e.Logger.Info("starting initially")
 	process, err := node.StartNode(e.Cfg, e.Logger, &e.Process, true, false, flags)
 	if err != nil {
@@ -34,50 +76,124 @@ func (e *Executor) InitialStart(flags []string) error {
 
 	e.Process.Id = process.Pid
 	e.Process.GhostMode = false
+	e.setRunning(true, false)
+	e.watch(process.Pid)
 
+	e.runPostHooks(hooks.EventStart, e.hookEnv())
 	return nil
 }
 
+// SetHeights sets the latest heights of the node and the pool, which are passed to the hooks.
+func (e *Executor) SetHeights(nodeHeight, poolHeight int) {
+	e.nodeHeight = nodeHeight
+	e.poolHeight = poolHeight
+}
+
 // EnableGhostMode activates the Ghost Mode by starting the node in GhostMode if it is not already enabled.
 // If not, it shuts down the node running in NormalMode, initiates the GhostMode and updates the process ID
-// and GhostMode upon success.
-func (e *Executor) EnableGhostMode(flags []string) error {
+// and GhostMode upon success. The ghost-enable hooks are run around the switch.
+func (e *Executor) EnableGhostMode(ctx context.Context, flags []string) error {
+	if e.Process.GhostMode {
+		return nil
+	}
+
+	if err := e.Hooks.Run(hooks.EventGhostEnable, hooks.PhasePre, e.hookEnv()); err != nil {
+		return err
+	}
+	if err := e.enableGhostMode(ctx, flags); err != nil {
+		return err
+	}
+	e.setRunning(true, false)
+	e.runPostHooks(hooks.EventGhostEnable, e.hookEnv())
+	return nil
+}
+
+func (e *Executor) enableGhostMode(ctx context.Context, flags []string) error {
 	if !e.Process.GhostMode {
-		if err := node.ShutdownNode(&e.Process); err != nil {
-			e.Logger.Error("could not shutdown node", "err", err)
+		start := time.Now()
+		if err := e.Shutdown(); err != nil {
+			return fmt.Errorf("could not shutdown node: %s", err)
 		}
 		e.Logger.Info("successfully shut down node", "mode", "normal")
 
-		time.Sleep(time.Second * time.Duration(10))
-
-		process, err := node.StartGhostNode(e.Cfg, e.Logger, &e.Process, false, flags)
+		process, err := node.StartGhostNode(e.Cfg, e.Logger, &e.Process, false, false, flags)
 		if err != nil {
 			return fmt.Errorf("Ghost Mode enabling failed: %s", err)
 		} else {
 			if process != nil && process.Pid > 0 {
 				e.Process.Id = process.Pid
 				e.Process.GhostMode = true
+				e.restarted()
 				e.Logger.Info("node started in Ghost Mode")
 			} else {
 				return fmt.Errorf("enabling Ghost Mode failed: process is not defined")
 			}
 		}
+		go e.observeDowntime(ctx, start, ModeGhost)
+
+		if err := e.verifyIsolation(ctx); err != nil {
+			return fmt.Errorf("Ghost Mode verification failed: %s", err)
+		}
 	}
 	return nil
 }
 
+// verifyIsolation waits until the RPC of the node started in Ghost Mode responds and verifies that
+// the node isn't connected to any peer. A node whose RPC doesn't respond in time, e.g. because it's
+// still replaying blocks, isn't treated as connected and is kept in Ghost Mode.
+func (e *Executor) verifyIsolation(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	for {
+		peers, err := e.Peers.Peers(ctx)
+		if err == nil {
+			if len(peers) > 0 {
+				return fmt.Errorf("node is still connected to %d peers", len(peers))
+			}
+			e.Logger.Info("verified that node in Ghost Mode has no peers")
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			e.Logger.Error("could not verify that node in Ghost Mode has no peers", "err", err)
+			if e.Metrics != nil {
+				e.Metrics.GhostIsolationUnverified.Inc()
+			}
+			return nil
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
 // EnableNormalMode enables the Normal Mode by starting the node in NormalMode if it is not already enabled.
 // If the GhostMode is active, it shuts down the node, starts the NormalMode with the provided parameters
-// and updates the process ID and GhostMode upon success.
-func (e *Executor) EnableNormalMode(flags []string) error {
+// and updates the process ID and GhostMode upon success. The normal-enable hooks are run around the switch.
+func (e *Executor) EnableNormalMode(ctx context.Context, flags []string) error {
+	if !e.Process.GhostMode {
+		return nil
+	}
+
+	if err := e.Hooks.Run(hooks.EventNormalEnable, hooks.PhasePre, e.hookEnv()); err != nil {
+		return err
+	}
+	if err := e.enableNormalMode(ctx, flags); err != nil {
+		return err
+	}
+	e.setRunning(true, false)
+	e.runPostHooks(hooks.EventNormalEnable, e.hookEnv())
+	return nil
+}
+
+func (e *Executor) enableNormalMode(ctx context.Context, flags []string) error {
 	if e.Process.GhostMode {
-		if err := node.ShutdownNode(&e.Process); err != nil {
-			e.Logger.Error("could not shutdown node", "err", err)
+		start := time.Now()
+		if err := e.Shutdown(); err != nil {
+			return fmt.Errorf("could not shutdown node: %s", err)
 		}
 		e.Logger.Info("successfully shut down node", "mode", "ghost")
 
-		time.Sleep(time.Second * time.Duration(10))
-
 		process, err := node.StartNode(e.Cfg, e.Logger, &e.Process, false, false, flags)
 		if err != nil {
 			return fmt.Errorf("Ghost Mode disabling failed: %s", err)
@@ -85,60 +201,324 @@ func (e *Executor) EnableNormalMode(flags []string) error {
 			if process != nil && process.Pid > 0 {
 				e.Process.Id = process.Pid
 				e.Process.GhostMode = false
+				e.restarted()
 				e.Logger.Info("Node started in Normal Mode", "pId", process.Pid)
 			} else {
 				return fmt.Errorf("Ghost Mode disabling failed: process is not defined")
 			}
 		}
+		go e.observeDowntime(ctx, start, ModeNormal)
 	}
 	return nil
 }
 
-func (e *Executor) PruneBlocks(homePath string, pruneHeight int, flags []string) error {
-	if err := e.Shutdown(); err != nil {
-		e.Logger.Error("could not shutdown node process", "err", err)
+// PruneBlocks shuts down the node, prunes the blocks until the given height and starts the node again
+// in its current mode. The prune hooks are run before the shutdown and after the restart. If the blocks
+// can't be pruned, the node is started again and ErrPruningFailed is returned.
+func (e *Executor) PruneBlocks(ctx context.Context, homePath string, pruneHeight int, flags []string) error {
+	env := e.hookEnv()
+	env.PruneHeight = pruneHeight
+	if err := e.Hooks.Run(hooks.EventPrune, hooks.PhasePre, env); err != nil {
 		return err
 	}
-	err := store.PruneBlocks(homePath, int64(pruneHeight)-1, e.Logger)
+
+	e.Notifier.Notify(notification.Event{
+		Type:     notification.EventPruneStarted,
+		Severity: notification.SeverityInfo,
+		Message:  fmt.Sprintf("pruning blocks until height %d", pruneHeight),
+		Mode:     e.mode(),
+	})
+
+	blocks, err := e.pruneBlocks(ctx, homePath, pruneHeight, flags)
 	if err != nil {
-		e.Logger.Error("could not prune blocks, exiting")
+		e.Notifier.Notify(notification.Event{
+			Type:     notification.EventPruneFailed,
+			Severity: notification.SeverityCritical,
+			Message:  fmt.Sprintf("pruning blocks until height %d failed: %s", pruneHeight, err),
+			Mode:     e.mode(),
+		})
 		return err
 	}
 
+	e.Notifier.Notify(notification.Event{
+		Type:     notification.EventPruneFinished,
+		Severity: notification.SeverityInfo,
+		Message:  fmt.Sprintf("pruned %d blocks until height %d", blocks, pruneHeight),
+		Mode:     e.mode(),
+	})
+	e.runPostHooks(hooks.EventPrune, env)
+	return nil
+}
+
+func (e *Executor) pruneBlocks(ctx context.Context, homePath string, pruneHeight int, flags []string) (uint64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	if err := e.Shutdown(); err != nil {
+		e.Logger.Error("could not shutdown node process", "err", err)
+		return 0, err
+	}
+	blocks, base, err := store.PruneBlocks(homePath, int64(pruneHeight)-1, e.Logger)
+	if err != nil {
+		e.Logger.Error("could not prune blocks, starting node again", "err", err)
+		if err := e.start(flags); err != nil {
+			return 0, err
+		}
+		return 0, fmt.Errorf("%w: %s", ErrPruningFailed, err)
+	}
+	if e.Metrics != nil {
+		e.Metrics.PruneRuns.Inc()
+		e.Metrics.PrunedBlocks.Add(float64(blocks))
+	}
+	e.SetBaseHeight(base)
+
+	// The node isn't started again if the supervysor is stopped while the blocks are pruned.
+	if err := ctx.Err(); err != nil {
+		return blocks, err
+	}
+
+	if err := e.start(flags); err != nil {
+		return 0, err
+	}
+
+	if e.Metrics != nil {
+		e.Metrics.PruneDuration.Observe(time.Since(start).Seconds())
+	}
+	return blocks, nil
+}
+
+// Restart shuts down the node and starts it again in its current mode, e.g. because its RPC is hung.
+func (e *Executor) Restart(flags []string) error {
+	if err := e.Shutdown(); err != nil {
+		return fmt.Errorf("could not shutdown node: %s", err)
+	}
+	return e.start(flags)
+}
+
+// start starts the node again in its current mode after it was shut down.
+func (e *Executor) start(flags []string) error {
 	if e.Process.GhostMode {
-		process, err := node.StartGhostNode(e.Cfg, e.Logger, &e.Process, true, flags)
+		process, err := node.StartGhostNode(e.Cfg, e.Logger, &e.Process, true, false, flags)
 		if err != nil {
 			return fmt.Errorf("Ghost Mode enabling failed: %s", err)
-		} else {
-			if process != nil && process.Pid > 0 {
-				e.Process.Id = process.Pid
-				e.Process.GhostMode = true
-				e.Logger.Info("node started in GhostMode after pruning blocks")
-			} else {
-				return fmt.Errorf("enabling Ghost Mode failed: process is not defined")
-			}
 		}
-	} else {
-		process, err := node.StartNode(e.Cfg, e.Logger, &e.Process, false, true, flags)
-		if err != nil {
-			return fmt.Errorf("Ghost Mode disabling failed: %s", err)
-		} else {
-			if process != nil && process.Pid > 0 {
-				e.Process.Id = process.Pid
-				e.Process.GhostMode = false
-				e.Logger.Info("Node started in Normal Mode after pruning blocks", "pId", process.Pid)
-			} else {
-				return fmt.Errorf("GhostMode disabling failed: process is not defined")
-			}
+		if process == nil || process.Pid <= 0 {
+			return fmt.Errorf("enabling Ghost Mode failed: process is not defined")
 		}
+		e.Process.Id = process.Pid
+		e.restarted()
+		e.Logger.Info("node started in GhostMode", "pId", process.Pid)
+		return nil
+	}
+
+	process, err := node.StartNode(e.Cfg, e.Logger, &e.Process, false, true, flags)
+	if err != nil {
+		return fmt.Errorf("Ghost Mode disabling failed: %s", err)
+	}
+	if process == nil || process.Pid <= 0 {
+		return fmt.Errorf("GhostMode disabling failed: process is not defined")
 	}
+	e.Process.Id = process.Pid
+	e.restarted()
+	e.Logger.Info("Node started in Normal Mode", "pId", process.Pid)
 	return nil
 }
 
-func (e *Executor) GetHeight() (int, error) {
-	return node.GetNodeHeight(e.Logger, &e.Process, e.Cfg.ABCIEndpoint)
+// GetStatus returns the status of the node, its latest height is also used to answer the health checks.
+// Errors wrap node.ErrNodeStarting, node.ErrNodeUnresponsive or node.ErrInvalidResponse, which distinguish
+// a node which is still starting from a hung node.
+func (e *Executor) GetStatus(ctx context.Context) (node.Status, error) {
+	status, err := node.GetNodeStatus(ctx, e.Logger, &e.Process, e.Cfg.ABCIEndpoint)
+	if err != nil {
+		return node.Status{}, err
+	}
+	e.updateHealth(func(h *Health) {
+		h.NodeHeight = int(status.LatestHeight)
+	})
+	return status, nil
 }
 
+// Shutdown shuts down the node process. Until the node is started again, it's reported as restarting.
 func (e *Executor) Shutdown() error {
-	return node.ShutdownNode(&e.Process)
+	pid := e.Process.Id
+	e.setRunning(false, true)
+	e.expectedExit.Store(int64(pid))
+	if err := node.ShutdownNode(e.Cfg, e.Logger, &e.Process); err != nil {
+		// The node won't be started again, it's only reported as running if its process is still known.
+		e.setRunning(e.Process.Id != -1, false)
+		return err
+	}
+	if e.Metrics != nil && pid != -1 && e.crashedExit.Load() != int64(pid) {
+		e.Metrics.NodeExits.WithLabelValues(strconv.Itoa(e.Process.ExitCode)).Inc()
+	}
+	return nil
+}
+
+// restarted records a restart of the node process.
+func (e *Executor) restarted() {
+	if e.Metrics != nil {
+		e.Metrics.NodeRestarts.Inc()
+	}
+	e.setRunning(true, false)
+	e.watch(e.Process.Id)
+}
+
+// watch reports a crash if the node process exits without being shut down by the supervysor.
+func (e *Executor) watch(pid int) {
+	done := node.Exited(pid)
+	if done == nil {
+		return
+	}
+
+	go func() {
+		<-done
+		if e.expectedExit.Load() == int64(pid) {
+			return
+		}
+
+		e.updateHealth(func(h *Health) {
+			h.Running = false
+		})
+		e.crashedExit.Store(int64(pid))
+		exitCode := node.ExitCode(pid)
+		if e.Metrics != nil {
+			e.Metrics.NodeExits.WithLabelValues(strconv.Itoa(exitCode)).Inc()
+		}
+		e.Logger.Error("node process exited unexpectedly", "pId", pid, "exit-code", exitCode)
+		e.Notifier.Notify(notification.Event{
+			Type:     notification.EventNodeCrash,
+			Severity: notification.SeverityCritical,
+			Message:  fmt.Sprintf("node process exited unexpectedly with exit code %d", exitCode),
+		})
+	}()
+}
+
+// runPostHooks runs the post hooks of an event which already happened. A failed post hook can't undo
+// the event, so failures are only logged.
+func (e *Executor) runPostHooks(event string, env hooks.Env) {
+	if err := e.Hooks.Run(event, hooks.PhasePost, env); err != nil {
+		e.Logger.Error(fmt.Sprintf("post-%s hook failed", event), "err", err)
+	}
+}
+
+func (e *Executor) hookEnv() hooks.Env {
+	return hooks.Env{Mode: e.mode(), NodeHeight: e.nodeHeight, PoolHeight: e.poolHeight}
+}
+
+func (e *Executor) mode() string {
+	if e.Process.GhostMode {
+		return ModeGhost
+	}
+	return ModeNormal
+}
+
+// observeDowntime waits until the RPC of the restarted node responds and records the time since the
+// shutdown as downtime of the switch to the given mode. It's run in the background, so the switch doesn't
+// wait for the node.
+func (e *Executor) observeDowntime(ctx context.Context, start time.Time, mode string) {
+	if e.Metrics == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	for {
+		if _, err := e.Peers.Peers(ctx); err == nil {
+			e.Metrics.SwitchDowntime.WithLabelValues(mode).Observe(time.Since(start).Seconds())
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			e.Logger.Error("node RPC did not respond after switch, downtime not recorded", "mode", mode)
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// Stop shuts down the node gracefully and restores the address book which is hidden in Ghost Mode,
+// so the node can also be started without the supervysor afterwards. Since the supervysor is stopped
+// anyway, failed pre-stop hooks don't abort the shutdown.
+func (e *Executor) Stop() error {
+	var errs []error
+	env := e.hookEnv()
+	if err := e.Hooks.Run(hooks.EventStop, hooks.PhasePre, env); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := e.Shutdown(); err != nil {
+		errs = append(errs, fmt.Errorf("could not shutdown node: %w", err))
+	}
+
+	if e.Process.GhostMode {
+		addrBookPath := filepath.Join(e.Cfg.HomePath, "config", "addrbook.json")
+		if err := helpers.MoveAddressBook(false, addrBookPath, e.Logger); err != nil {
+			errs = append(errs, fmt.Errorf("could not restore address book: %w", err))
+		} else {
+			e.Logger.Info("address book restored")
+		}
+	}
+	e.Process.GhostMode = false
+	e.setRunning(false, false)
+
+	e.runPostHooks(hooks.EventStop, env)
+
+	return errors.Join(errs...)
+}
+
+// VerifyGhostMode samples the node over the configured window after Ghost Mode was enabled and checks
+// that it stopped syncing. If the node is still syncing, Ghost Mode is enabled again with stricter
+// settings, which only let the node listen on the loopback interface. The report of the first
+// verification is returned, an error only if the node still leaks.
+func (e *Executor) VerifyGhostMode(ctx context.Context, flags []string) (node.LeakReport, error) {
+	if !e.Process.GhostMode || e.Cfg.GhostVerifyWindow <= 0 {
+		return node.LeakReport{}, nil
+	}
+
+	verifier := node.NewGhostVerifier(e.Peers, time.Duration(e.Cfg.GhostVerifyWindow)*time.Second)
+
+	// A node which can't be sampled (e.g. still replaying blocks) isn't treated as leaking.
+	report, err := verifier.Verify(ctx)
+	if err != nil {
+		e.Logger.Error("could not verify Ghost Mode", "err", err)
+		return report, nil
+	}
+	if !report.Leaking {
+		e.Logger.Info("verified that node stopped syncing in Ghost Mode", "height", report.EndHeight, "samples", report.Samples)
+		return report, nil
+	}
+
+	e.Logger.Error("node is still syncing in Ghost Mode, retrying with stricter settings", "start-height", report.StartHeight, "end-height", report.EndHeight, "max-peers", report.MaxPeers)
+
+	if err = e.Shutdown(); err != nil {
+		e.Logger.Error("could not shutdown node", "err", err)
+	}
+
+	process, err := node.StartGhostNode(e.Cfg, e.Logger, &e.Process, true, true, flags)
+	if err != nil {
+		return report, fmt.Errorf("Ghost Mode enabling failed: %s", err)
+	}
+	if process == nil || process.Pid <= 0 {
+		return report, fmt.Errorf("enabling Ghost Mode failed: process is not defined")
+	}
+	e.Process.Id = process.Pid
+	e.Process.GhostMode = true
+	e.restarted()
+	e.Logger.Info("node restarted in Ghost Mode with stricter settings")
+
+	retry, err := verifier.Verify(ctx)
+	if err != nil {
+		e.Logger.Error("could not verify Ghost Mode", "err", err)
+		return report, nil
+	}
+	if retry.Leaking {
+		return report, fmt.Errorf("node is still syncing in Ghost Mode: height advanced from %d to %d with up to %d peers", retry.StartHeight, retry.EndHeight, retry.MaxPeers)
+	}
+
+	e.Logger.Info("verified that node stopped syncing in Ghost Mode", "height", retry.EndHeight, "samples", retry.Samples)
+	return report, nil
 }
@@ -0,0 +1,150 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Health is a snapshot of the node state which is used to answer health and readiness checks. It's
+// updated by the executor and can be read concurrently.
+type Health struct {
+	// Running is true while the node process is started by the supervysor.
+	Running bool
+	// Restarting is true while the node is shut down for a mode switch or pruning.
+	Restarting bool
+	Mode       string
+	// NodeHeight is the latest height returned by GetStatus.
+	NodeHeight int
+	// BaseHeight is the lowest height stored in the blockstore, 0 if unknown.
+	BaseHeight int64
+	UpdatedAt  time.Time
+}
+
+// healthResponse is the JSON body returned by the health endpoints.
+type healthResponse struct {
+	Status     string `json:"status"`
+	Reason     string `json:"reason,omitempty"`
+	Mode       string `json:"mode"`
+	NodeHeight int    `json:"node_height"`
+	BaseHeight int64  `json:"base_height"`
+	Height     int64  `json:"height,omitempty"`
+	UpdatedAt  string `json:"updated_at,omitempty"`
+}
+
+// Health returns the current health snapshot of the node.
+func (e *Executor) Health() Health {
+	e.healthMu.RLock()
+	defer e.healthMu.RUnlock()
+	return e.health
+}
+
+// SetBaseHeight sets the lowest height stored in the blockstore. It can only be read while the node is
+// stopped, so it's set before the initial start and updated after every pruning.
+func (e *Executor) SetBaseHeight(base int64) {
+	e.updateHealth(func(h *Health) {
+		h.BaseHeight = base
+	})
+	if e.Metrics != nil {
+		e.Metrics.BlockstoreBase.Set(float64(base))
+	}
+}
+
+func (e *Executor) updateHealth(update func(h *Health)) {
+	e.healthMu.Lock()
+	defer e.healthMu.Unlock()
+	update(&e.health)
+	e.health.UpdatedAt = time.Now()
+}
+
+// setRunning records whether the node process is running and whether it's shut down to be restarted.
+func (e *Executor) setRunning(running bool, restarting bool) {
+	mode := e.mode()
+	e.updateHealth(func(h *Health) {
+		h.Running = running
+		h.Restarting = restarting
+		h.Mode = mode
+	})
+}
+
+// unavailable returns the reason why the node can't serve any data, or an empty string if it can.
+func (h Health) unavailable() string {
+	switch {
+	case h.Restarting:
+		return "node is restarting"
+	case !h.Running:
+		return "node is not running"
+	case h.NodeHeight <= 0:
+		return "node height is unknown"
+	}
+	return ""
+}
+
+// HealthHandler returns the handler for the health endpoints:
+//
+//   - /healthz responds 200 as long as the node is running or restarted by the supervysor.
+//   - /readyz responds 200 if the node is running, not restarting and its height is known.
+//   - /can-serve?height=N responds 200 if the node is ready and the blockstore base <= N <= node height.
+//
+// All other cases respond 503 (400 for an invalid height) with the reason in the JSON body.
+func (e *Executor) HealthHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		h := e.Health()
+		reason := ""
+		if !h.Running && !h.Restarting {
+			reason = "node is not running"
+		}
+		writeHealth(w, h, reason, 0)
+	})
+
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		h := e.Health()
+		writeHealth(w, h, h.unavailable(), 0)
+	})
+
+	mux.HandleFunc("/can-serve", func(w http.ResponseWriter, r *http.Request) {
+		height, err := strconv.ParseInt(r.URL.Query().Get("height"), 10, 64)
+		if err != nil || height <= 0 {
+			http.Error(w, "height must be a positive integer", http.StatusBadRequest)
+			return
+		}
+
+		h := e.Health()
+		reason := h.unavailable()
+		if reason == "" {
+			if height < h.BaseHeight {
+				reason = fmt.Sprintf("height %d was pruned, blockstore base is %d", height, h.BaseHeight)
+			} else if height > int64(h.NodeHeight) {
+				reason = fmt.Sprintf("height %d was not reached yet, node height is %d", height, h.NodeHeight)
+			}
+		}
+		writeHealth(w, h, reason, height)
+	})
+
+	return mux
+}
+
+func writeHealth(w http.ResponseWriter, h Health, reason string, height int64) {
+	resp := healthResponse{
+		Status:     "ok",
+		Reason:     reason,
+		Mode:       h.Mode,
+		NodeHeight: h.NodeHeight,
+		BaseHeight: h.BaseHeight,
+		Height:     height,
+	}
+	if !h.UpdatedAt.IsZero() {
+		resp.UpdatedAt = h.UpdatedAt.UTC().Format(time.RFC3339)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if reason != "" {
+		resp.Status = "unavailable"
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
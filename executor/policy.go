@@ -0,0 +1,154 @@
+package executor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/KYVENetwork/supervysor/types"
+)
+
+const (
+	ModeNormal = "normal"
+	ModeGhost  = "ghost"
+)
+
+// Action is the decision of a policy for the current supervision interval.
+type Action string
+
+const (
+	// ActionKeep keeps the node in its current mode.
+	ActionKeep Action = "keep"
+	// ActionGhost enables or keeps Ghost Mode.
+	ActionGhost Action = "ghost"
+	// ActionNormal enables or keeps Normal Mode.
+	ActionNormal Action = "normal"
+)
+
+// Observation contains everything a policy can base its decision on.
+type Observation struct {
+	Mode         string
+	NodeHeight   int
+	PoolHeight   int
+	PruneHeight  int
+	PoolStatus   string
+	PoolSettings types.PoolSettingsType
+	// DiskUsage is the size of the data directory in bytes, 0 if it isn't measured.
+	DiskUsage           float64
+	TimeSinceLastSwitch time.Duration
+	TimeSinceLastPrune  time.Duration
+}
+
+// Decision is the action a policy decided on. Prune is set if the pruning interval was reached, then blocks
+// are pruned until PruneHeight before the action is applied. Nothing is pruned if PruneHeight is 0, e.g.
+// because the node is ahead of the pool in Normal Mode, but the pruning interval starts again.
+type Decision struct {
+	Action      Action
+	Reason      string
+	Prune       bool
+	PruneHeight int
+}
+
+// Policy decides which mode the node should run in and when blocks should be pruned. Policies don't
+// perform any actions themselves, which keeps them independent of the node process.
+type Policy interface {
+	Decide(obs Observation) Decision
+}
+
+// ThresholdPolicy is the default policy: Ghost Mode is enabled once the node is HeightDifferenceMax blocks
+// ahead of the pool and Normal Mode once it is only HeightDifferenceMin blocks ahead. Blocks are pruned every
+// PruningInterval, as long as the pool status allows it.
+type ThresholdPolicy struct {
+	HeightDifferenceMax int
+	HeightDifferenceMin int
+	PruningInterval     time.Duration
+
+	// StatusAction maps the pool status to an action, see Executor.StatusAction.
+	StatusAction func(status string) StatusAction
+	// Predictor is optional and used to enable Normal Mode before the pool catches up.
+	Predictor *Predictor
+}
+
+func (p *ThresholdPolicy) Decide(obs Observation) Decision {
+	statusAction := StatusActionContinue
+	if p.StatusAction != nil {
+		statusAction = p.StatusAction(obs.PoolStatus)
+	}
+
+	decision := p.decideMode(obs, statusAction)
+
+	if p.PruningInterval > 0 && statusAction == StatusActionContinue && obs.TimeSinceLastPrune > p.PruningInterval && obs.NodeHeight > 0 {
+		decision.Prune = true
+		if obs.Mode == ModeGhost {
+			// Only prune blocks which are already validated by the pool.
+			decision.PruneHeight = obs.PruneHeight
+			if obs.NodeHeight < decision.PruneHeight {
+				decision.PruneHeight = obs.NodeHeight
+			}
+		} else if obs.NodeHeight < obs.PruneHeight {
+			// In Normal Mode the node is only pruned while it's behind the pool, up to its own height.
+			decision.PruneHeight = obs.NodeHeight
+		}
+	}
+
+	return decision
+}
+
+func (p *ThresholdPolicy) decideMode(obs Observation, statusAction StatusAction) Decision {
+	heightDiff := obs.NodeHeight - obs.PoolHeight
+
+	switch {
+	case statusAction == StatusActionHold:
+		return Decision{Action: ActionKeep, Reason: fmt.Sprintf("pool status %s", obs.PoolStatus)}
+	case statusAction == StatusActionGhost:
+		return Decision{Action: ActionGhost, Reason: fmt.Sprintf("pool status %s", obs.PoolStatus)}
+	case heightDiff >= p.HeightDifferenceMax:
+		return Decision{Action: ActionGhost, Reason: "node reached max height"}
+	case obs.Mode == ModeGhost && p.Predictor != nil && p.Predictor.ShouldEnableNormalMode(obs.NodeHeight, obs.PoolHeight):
+		return Decision{Action: ActionNormal, Reason: "pool is about to catch up"}
+	case heightDiff > p.HeightDifferenceMin:
+		return Decision{Action: ActionKeep, Reason: "no threshold reached"}
+	default:
+		return Decision{Action: ActionNormal, Reason: "node reached min height"}
+	}
+}
+
+// MinDwellPolicy wraps another policy and prevents mode switches until the node has been running in its
+// current mode for at least MinDwell, so a flapping height difference can't restart the node every interval.
+type MinDwellPolicy struct {
+	Policy   Policy
+	MinDwell time.Duration
+}
+
+func (p *MinDwellPolicy) Decide(obs Observation) Decision {
+	decision := p.Policy.Decide(obs)
+
+	switches := (decision.Action == ActionGhost && obs.Mode != ModeGhost) ||
+		(decision.Action == ActionNormal && obs.Mode != ModeNormal)
+
+	// Falling behind the pool makes the node useless as a data source, so Normal Mode is never delayed then.
+	behind := obs.NodeHeight <= obs.PoolHeight
+
+	if switches && !behind && obs.TimeSinceLastSwitch < p.MinDwell {
+		decision.Action = ActionKeep
+		decision.Reason = fmt.Sprintf("%s (delayed by min dwell time, %s remaining)", decision.Reason, (p.MinDwell - obs.TimeSinceLastSwitch).Round(time.Second))
+	}
+	return decision
+}
+
+// NewPolicy creates the default threshold policy from the config, wrapped by a MinDwellPolicy if a
+// minimum dwell time is configured.
+func (e *Executor) NewPolicy(predictor *Predictor) Policy {
+	var policy Policy = &ThresholdPolicy{
+		HeightDifferenceMax: e.Cfg.HeightDifferenceMax,
+		HeightDifferenceMin: e.Cfg.HeightDifferenceMin,
+		PruningInterval:     time.Duration(e.Cfg.PruningInterval) * time.Hour,
+		StatusAction:        e.StatusAction,
+		Predictor:           predictor,
+	}
+
+	if e.Cfg.MinDwellTime > 0 {
+		policy = &MinDwellPolicy{Policy: policy, MinDwell: time.Duration(e.Cfg.MinDwellTime) * time.Second}
+	}
+
+	return policy
+}
@@ -0,0 +1,120 @@
+package executor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMinDwellPolicyDelaysSwitches(t *testing.T) {
+	policy := &MinDwellPolicy{
+		Policy:   &ThresholdPolicy{HeightDifferenceMax: 100, HeightDifferenceMin: 50},
+		MinDwell: 5 * time.Minute,
+	}
+
+	tests := []struct {
+		name string
+		obs  Observation
+		want Action
+	}{
+		{
+			name: "switch to Ghost Mode within min dwell time",
+			obs:  Observation{Mode: ModeNormal, NodeHeight: 1200, PoolHeight: 1000, TimeSinceLastSwitch: time.Minute},
+			want: ActionKeep,
+		},
+		{
+			name: "switch to Ghost Mode after min dwell time",
+			obs:  Observation{Mode: ModeNormal, NodeHeight: 1200, PoolHeight: 1000, TimeSinceLastSwitch: 10 * time.Minute},
+			want: ActionGhost,
+		},
+		{
+			name: "keep Ghost Mode within min dwell time",
+			obs:  Observation{Mode: ModeGhost, NodeHeight: 1200, PoolHeight: 1000, TimeSinceLastSwitch: time.Minute},
+			want: ActionGhost,
+		},
+		{
+			name: "switch to Normal Mode within min dwell time",
+			obs:  Observation{Mode: ModeGhost, NodeHeight: 1020, PoolHeight: 1000, TimeSinceLastSwitch: time.Minute},
+			want: ActionKeep,
+		},
+		{
+			name: "switch to Normal Mode within min dwell time if node is behind",
+			obs:  Observation{Mode: ModeGhost, NodeHeight: 1000, PoolHeight: 1000, TimeSinceLastSwitch: time.Minute},
+			want: ActionNormal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Decide(tt.obs); got.Action != tt.want {
+				t.Errorf("action = %s (%s), want %s", got.Action, got.Reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestThresholdPolicyPruning(t *testing.T) {
+	policy := &ThresholdPolicy{HeightDifferenceMax: 100, HeightDifferenceMin: 50, PruningInterval: time.Hour}
+
+	tests := []struct {
+		name       string
+		obs        Observation
+		wantAction Action
+		wantPrune  bool
+		wantHeight int
+	}{
+		{
+			name:       "Ghost Mode prunes until pool height",
+			obs:        Observation{Mode: ModeGhost, NodeHeight: 1200, PoolHeight: 1000, PruneHeight: 900, TimeSinceLastPrune: 2 * time.Hour},
+			wantAction: ActionGhost,
+			wantPrune:  true,
+			wantHeight: 900,
+		},
+		{
+			name:       "Ghost Mode prunes and switches to Normal Mode in the same interval",
+			obs:        Observation{Mode: ModeGhost, NodeHeight: 1020, PoolHeight: 1000, PruneHeight: 900, TimeSinceLastPrune: 2 * time.Hour},
+			wantAction: ActionNormal,
+			wantPrune:  true,
+			wantHeight: 900,
+		},
+		{
+			name:       "Normal Mode prunes until node height if node is behind",
+			obs:        Observation{Mode: ModeNormal, NodeHeight: 800, PoolHeight: 1000, PruneHeight: 900, TimeSinceLastPrune: 2 * time.Hour},
+			wantAction: ActionNormal,
+			wantPrune:  true,
+			wantHeight: 800,
+		},
+		{
+			name:       "Normal Mode restarts pruning interval without pruning if node is ahead",
+			obs:        Observation{Mode: ModeNormal, NodeHeight: 1020, PoolHeight: 1000, PruneHeight: 900, TimeSinceLastPrune: 2 * time.Hour},
+			wantAction: ActionNormal,
+			wantPrune:  true,
+			wantHeight: 0,
+		},
+		{
+			name:       "no pruning within pruning interval",
+			obs:        Observation{Mode: ModeGhost, NodeHeight: 1200, PoolHeight: 1000, PruneHeight: 900, TimeSinceLastPrune: 30 * time.Minute},
+			wantAction: ActionGhost,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := policy.Decide(tt.obs)
+			if got.Action != tt.wantAction || got.Prune != tt.wantPrune || got.PruneHeight != tt.wantHeight {
+				t.Errorf("decision = %s, prune %t until %d, want %s, prune %t until %d", got.Action, got.Prune, got.PruneHeight, tt.wantAction, tt.wantPrune, tt.wantHeight)
+			}
+		})
+	}
+}
+
+func TestMinDwellPolicyKeepsPruning(t *testing.T) {
+	policy := &MinDwellPolicy{
+		Policy:   &ThresholdPolicy{HeightDifferenceMax: 100, HeightDifferenceMin: 50, PruningInterval: time.Hour},
+		MinDwell: 5 * time.Minute,
+	}
+
+	got := policy.Decide(Observation{Mode: ModeGhost, NodeHeight: 1020, PoolHeight: 1000, PruneHeight: 900, TimeSinceLastSwitch: time.Minute, TimeSinceLastPrune: 2 * time.Hour})
+	if got.Action != ActionKeep || !got.Prune || got.PruneHeight != 900 {
+		t.Errorf("decision = %s, prune %t until %d, want keep, prune until 900", got.Action, got.Prune, got.PruneHeight)
+	}
+}
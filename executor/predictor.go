@@ -0,0 +1,111 @@
+package executor
+
+import (
+	"time"
+)
+
+const (
+	velocityWindow = time.Hour
+	// defaultCatchUpTime is assumed as long as no restart in Normal Mode has been measured.
+	defaultCatchUpTime = time.Hour
+)
+
+// Predictor estimates when the pool will catch up with a node in Ghost Mode, based on the rolling velocities
+// of the pool and the node. It measures how long the node needs after enabling Normal Mode until it syncs
+// again (peer discovery and catch-up) and recommends switching back to Normal Mode early enough, so the pool
+// never reaches the node height.
+type Predictor struct {
+	Pool *VelocityTracker
+	Node *VelocityTracker
+
+	// CatchUpTime is the measured time the node needs after a restart in Normal Mode until its height advances.
+	CatchUpTime time.Duration
+	// Margin is added to the catch-up time as a safety buffer.
+	Margin time.Duration
+
+	measuring     bool
+	normalSince   time.Time
+	normalHeight  int
+	measuredCount int
+}
+
+// NewPredictor creates a predictor with a safety margin in minutes.
+func NewPredictor(margin int) *Predictor {
+	return &Predictor{
+		Pool:        NewVelocityTracker(velocityWindow),
+		Node:        NewVelocityTracker(velocityWindow),
+		CatchUpTime: defaultCatchUpTime,
+		Margin:      time.Duration(margin) * time.Minute,
+	}
+}
+
+// Observe records the pool and node height. The node velocity is only tracked in Normal Mode once the node
+// syncs again, since the node doesn't sync in Ghost Mode and while it catches up after a restart.
+func (p *Predictor) Observe(nodeHeight int, poolHeight int, ghostMode bool, at time.Time) {
+	p.Pool.Add(poolHeight, at)
+
+	if ghostMode {
+		return
+	}
+	if !p.measuring {
+		p.Node.Add(nodeHeight, at)
+		return
+	}
+
+	// The first advancing height after enabling Normal Mode ends the catch-up measurement.
+	if nodeHeight > p.normalHeight {
+		measured := at.Sub(p.normalSince)
+		if p.measuredCount == 0 {
+			p.CatchUpTime = measured
+		} else {
+			// Exponential moving average, so a single fast restart doesn't hide slow ones.
+			p.CatchUpTime = (p.CatchUpTime*2 + measured) / 3
+		}
+		p.measuredCount++
+		p.measuring = false
+		p.Node.Add(nodeHeight, at)
+	}
+}
+
+// NormalModeEnabled starts measuring the catch-up time of the node from the height it had when Normal Mode
+// was enabled.
+func (p *Predictor) NormalModeEnabled(nodeHeight int, at time.Time) {
+	p.Node.Reset()
+	p.measuring = true
+	p.normalSince = at
+	p.normalHeight = nodeHeight
+}
+
+// GhostModeEnabled stops measuring, the node is not expected to sync anymore. The node velocity of the last
+// Normal Mode period is kept to predict the next switch.
+func (p *Predictor) GhostModeEnabled() {
+	p.measuring = false
+}
+
+// TimeUntilCaughtUp estimates the time until the pool reaches the node height. It returns false if the
+// pool velocity is not known yet or the pool doesn't advance.
+func (p *Predictor) TimeUntilCaughtUp(nodeHeight int, poolHeight int) (time.Duration, bool) {
+	velocity, ok := p.Pool.BlocksPerHour()
+	if !ok || velocity <= 0 {
+		return 0, false
+	}
+	hours := float64(nodeHeight-poolHeight) / velocity
+	return time.Duration(hours * float64(time.Hour)), true
+}
+
+// ShouldEnableNormalMode reports whether the node in Ghost Mode should switch back to Normal Mode now,
+// because the pool would otherwise catch up before the node syncs again. If the node synced slower than
+// the pool advances in the last Normal Mode period, its lead only shrinks once it syncs again, so Normal
+// Mode is enabled right away.
+func (p *Predictor) ShouldEnableNormalMode(nodeHeight int, poolHeight int) bool {
+	remaining, ok := p.TimeUntilCaughtUp(nodeHeight, poolHeight)
+	if !ok {
+		return false
+	}
+
+	poolVelocity, _ := p.Pool.BlocksPerHour()
+	if nodeVelocity, ok := p.Node.BlocksPerHour(); ok && nodeVelocity < poolVelocity {
+		return true
+	}
+	return remaining <= p.CatchUpTime+p.Margin
+}
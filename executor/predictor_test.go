@@ -0,0 +1,68 @@
+package executor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPredictorMeasuresCatchUpOnlyAfterSwitch(t *testing.T) {
+	p := NewPredictor(0)
+	start := time.Unix(0, 0)
+
+	// Without a switch to Normal Mode, advancing heights don't produce a catch-up measurement.
+	p.Observe(100, 50, false, start)
+	p.Observe(110, 55, false, start.Add(time.Minute))
+	if p.CatchUpTime != defaultCatchUpTime {
+		t.Fatalf("catch-up time = %s, want default %s", p.CatchUpTime, defaultCatchUpTime)
+	}
+
+	p.GhostModeEnabled()
+	p.Observe(110, 60, true, start.Add(2*time.Minute))
+
+	p.NormalModeEnabled(110, start.Add(3*time.Minute))
+	p.Observe(110, 65, false, start.Add(4*time.Minute))
+	p.Observe(110, 70, false, start.Add(8*time.Minute))
+	p.Observe(111, 75, false, start.Add(13*time.Minute))
+
+	if p.CatchUpTime != 10*time.Minute {
+		t.Fatalf("catch-up time = %s, want 10m", p.CatchUpTime)
+	}
+}
+
+func TestPredictorEnablesNormalModeIfNodeIsSlowerThanPool(t *testing.T) {
+	p := NewPredictor(0)
+	p.CatchUpTime = time.Minute
+	start := time.Unix(0, 0)
+
+	// The node syncs 60 blocks per hour in Normal Mode.
+	for i := 0; i <= 10; i++ {
+		p.Observe(1000+i, 100+2*i, false, start.Add(time.Duration(i)*time.Minute))
+	}
+	p.GhostModeEnabled()
+
+	// The pool advances 120 blocks per hour and needs hours to catch up with the node.
+	for i := 0; i <= 10; i++ {
+		p.Observe(1010, 120+2*i, true, start.Add(time.Duration(10+i)*time.Minute))
+	}
+
+	if remaining, _ := p.TimeUntilCaughtUp(1010, 140); remaining <= p.CatchUpTime {
+		t.Fatalf("remaining time %s is within the catch-up time", remaining)
+	}
+	if !p.ShouldEnableNormalMode(1010, 140) {
+		t.Errorf("expected Normal Mode for a node which syncs slower than the pool")
+	}
+
+	// A node which syncs faster than the pool stays in Ghost Mode until the catch-up time is reached.
+	fast := NewPredictor(0)
+	fast.CatchUpTime = time.Minute
+	for i := 0; i <= 10; i++ {
+		fast.Observe(1000+10*i, 100+2*i, false, start.Add(time.Duration(i)*time.Minute))
+	}
+	fast.GhostModeEnabled()
+	for i := 0; i <= 10; i++ {
+		fast.Observe(1100, 120+2*i, true, start.Add(time.Duration(10+i)*time.Minute))
+	}
+	if fast.ShouldEnableNormalMode(1100, 140) {
+		t.Errorf("expected Ghost Mode for a node which syncs faster than the pool")
+	}
+}
@@ -0,0 +1,182 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/KYVENetwork/supervysor/node"
+)
+
+// StallPolicy defines how the supervysor reacts to a node whose height stopped advancing in Normal Mode.
+type StallPolicy string
+
+const (
+	// StallPolicyAlert only notifies about the stalled node.
+	StallPolicyAlert StallPolicy = "alert"
+	// StallPolicyRestart restarts the node with the configured seeds and without its address book.
+	StallPolicyRestart StallPolicy = "restart"
+	// StallPolicyRotateSeeds restarts the node like StallPolicyRestart, but with the next of the seed lists.
+	StallPolicyRotateSeeds StallPolicy = "rotate-seeds"
+	// StallPolicyStop stops the node and the supervysor, so no data of a broken node is served.
+	StallPolicyStop StallPolicy = "stop"
+)
+
+// ValidateStallPolicy checks that the configured stall policy is known. An empty policy is handled as alert.
+func ValidateStallPolicy(policy string) error {
+	switch StallPolicy(policy) {
+	case "", StallPolicyAlert, StallPolicyRestart, StallPolicyRotateSeeds, StallPolicyStop:
+		return nil
+	}
+	return fmt.Errorf("unknown stall policy %s", policy)
+}
+
+// StallState describes how the supervysor should react to the height of a node in Normal Mode.
+type StallState int
+
+const (
+	// StallNone means the height advanced within the window.
+	StallNone StallState = iota
+	// StallDetected is returned once per window while the height doesn't advance, the policy is applied.
+	StallDetected
+	// StallOngoing means the node is still stalled, but the policy was already applied in this window.
+	StallOngoing
+	// StallEscalate means the node is stalled longer than the stop window and should be stopped.
+	StallEscalate
+)
+
+// StallDetector detects a node in Normal Mode whose height doesn't advance, e.g. because it has no peers,
+// a consensus failure or an app hash mismatch. A window of zero disables the detection and a stop window
+// of zero disables the escalation.
+type StallDetector struct {
+	Window     time.Duration
+	StopWindow time.Duration
+
+	height int
+	since  time.Time
+	acted  time.Time
+}
+
+// NewStallDetector creates a stall detector with the window and stop window in seconds.
+func NewStallDetector(window int, stopWindow int) *StallDetector {
+	return &StallDetector{
+		Window:     time.Duration(window) * time.Second,
+		StopWindow: time.Duration(stopWindow) * time.Second,
+	}
+}
+
+// Observe registers the height of the node in Normal Mode and returns the resulting stall state.
+func (d *StallDetector) Observe(height int, now time.Time) StallState {
+	if d.Window <= 0 {
+		return StallNone
+	}
+	if d.since.IsZero() || height > d.height {
+		d.height = height
+		d.since = now
+		d.acted = time.Time{}
+		return StallNone
+	}
+
+	duration := now.Sub(d.since)
+	if d.StopWindow > 0 && duration >= d.StopWindow {
+		return StallEscalate
+	}
+	if duration < d.Window {
+		return StallNone
+	}
+	if d.acted.IsZero() || now.Sub(d.acted) >= d.Window {
+		d.acted = now
+		return StallDetected
+	}
+	return StallOngoing
+}
+
+// Reset restarts the detection, e.g. after the node was switched to Ghost Mode where its height is expected
+// to stay flat, or after it was restarted for pruning.
+func (d *StallDetector) Reset() {
+	d.since = time.Time{}
+	d.acted = time.Time{}
+}
+
+// Duration returns how long the height of the node didn't advance.
+func (d *StallDetector) Duration(now time.Time) time.Duration {
+	if d.since.IsZero() {
+		return 0
+	}
+	return now.Sub(d.since)
+}
+
+// RecoverStall applies the stall policy to a node whose height stopped advancing in Normal Mode or whose RPC
+// didn't come up. The restart policies restart the node with fresh seeds, in Ghost Mode the node doesn't
+// use seeds and is only restarted. The alert and stop policies don't change the node, since notifying and
+// stopping is handled by the caller.
+func (e *Executor) RecoverStall(flags []string) error {
+	if e.Metrics != nil {
+		e.Metrics.NodeStalls.WithLabelValues(string(e.StallPolicy())).Inc()
+	}
+
+	switch policy := e.StallPolicy(); {
+	case e.Process.GhostMode && (policy == StallPolicyRestart || policy == StallPolicyRotateSeeds):
+		return e.Restart(flags)
+	case policy == StallPolicyRestart:
+		return e.RestartWithSeeds(e.Cfg.Seeds, flags)
+	case policy == StallPolicyRotateSeeds:
+		lists := append([]string{e.Cfg.Seeds}, e.Cfg.SeedLists...)
+		e.seedList = (e.seedList + 1) % len(lists)
+		return e.RestartWithSeeds(lists[e.seedList], flags)
+	}
+	return nil
+}
+
+// StopOnStall returns whether the node should be stopped as soon as a stall is detected.
+func (e *Executor) StopOnStall() bool {
+	return e.StallPolicy() == StallPolicyStop
+}
+
+// StallPolicy returns the configured stall policy, alert if none is configured.
+func (e *Executor) StallPolicy() StallPolicy {
+	if e.Cfg.StallPolicy == "" {
+		return StallPolicyAlert
+	}
+	return StallPolicy(e.Cfg.StallPolicy)
+}
+
+// RestartWithSeeds restarts the node in Normal Mode with the given seeds. The address book is moved to
+// addrbook.json.stalled before, so the node has to discover its peers through the seeds again. If the node
+// stalls again, the existing backup is kept, since it's the address book the node had before the first stall.
+func (e *Executor) RestartWithSeeds(seeds string, flags []string) error {
+	if e.Process.GhostMode {
+		return fmt.Errorf("node is not running in Normal Mode")
+	}
+
+	if err := e.Shutdown(); err != nil {
+		return fmt.Errorf("could not shutdown node: %s", err)
+	}
+
+	addrBookPath := filepath.Join(e.Cfg.HomePath, "config", "addrbook.json")
+	if _, err := os.Stat(addrBookPath + ".stalled"); err == nil {
+		if err = os.Remove(addrBookPath); err != nil && !os.IsNotExist(err) {
+			e.Logger.Error("could not remove address book", "err", err)
+		}
+	} else if err = os.Rename(addrBookPath, addrBookPath+".stalled"); err != nil && !os.IsNotExist(err) {
+		e.Logger.Error("could not move address book", "err", err)
+	}
+
+	// The seeds are only passed to the node on the initial start.
+	cfg := *e.Cfg
+	cfg.Seeds = seeds
+	process, err := node.StartNode(&cfg, e.Logger, &e.Process, true, true, flags)
+	if err != nil {
+		return fmt.Errorf("could not restart node with fresh seeds: %s", err)
+	}
+	if process == nil || process.Pid <= 0 {
+		return fmt.Errorf("could not restart node with fresh seeds: process is not defined")
+	}
+
+	e.Process.Id = process.Pid
+	e.Process.GhostMode = false
+	e.restarted()
+	e.Logger.Info("node restarted with fresh seeds", "pId", process.Pid, "seeds", seeds)
+	return nil
+}
@@ -0,0 +1,140 @@
+package executor
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cosmossdk.io/log"
+
+	"github.com/KYVENetwork/supervysor/types"
+)
+
+func TestStallDetectorWindow(t *testing.T) {
+	d := NewStallDetector(900, 0)
+	start := time.Unix(0, 0)
+
+	if state := d.Observe(100, start); state != StallNone {
+		t.Fatalf("first observation = %d, want StallNone", state)
+	}
+	if state := d.Observe(100, start.Add(899*time.Second)); state != StallNone {
+		t.Fatalf("state before window = %d, want StallNone", state)
+	}
+	if state := d.Observe(100, start.Add(900*time.Second)); state != StallDetected {
+		t.Fatalf("state at window = %d, want StallDetected", state)
+	}
+	// The policy is applied once per window.
+	if state := d.Observe(100, start.Add(1000*time.Second)); state != StallOngoing {
+		t.Fatalf("state within same window = %d, want StallOngoing", state)
+	}
+	if state := d.Observe(100, start.Add(1800*time.Second)); state != StallDetected {
+		t.Fatalf("state after next window = %d, want StallDetected", state)
+	}
+	if d.Duration(start.Add(1800*time.Second)) != 30*time.Minute {
+		t.Fatalf("duration = %s, want 30m", d.Duration(start.Add(1800*time.Second)))
+	}
+}
+
+func TestStallDetectorProgressResets(t *testing.T) {
+	d := NewStallDetector(900, 0)
+	start := time.Unix(0, 0)
+
+	d.Observe(100, start)
+	if state := d.Observe(100, start.Add(900*time.Second)); state != StallDetected {
+		t.Fatalf("state at window = %d, want StallDetected", state)
+	}
+
+	// An advancing height restarts the window.
+	if state := d.Observe(101, start.Add(1000*time.Second)); state != StallNone {
+		t.Fatalf("state after progress = %d, want StallNone", state)
+	}
+	if state := d.Observe(101, start.Add(1800*time.Second)); state != StallNone {
+		t.Fatalf("state within new window = %d, want StallNone", state)
+	}
+	if state := d.Observe(101, start.Add(1900*time.Second)); state != StallDetected {
+		t.Fatalf("state after new window = %d, want StallDetected", state)
+	}
+
+	d.Reset()
+	if d.Duration(start.Add(1900*time.Second)) != 0 {
+		t.Fatalf("duration after reset = %s, want 0", d.Duration(start.Add(1900*time.Second)))
+	}
+	if state := d.Observe(101, start.Add(2000*time.Second)); state != StallNone {
+		t.Fatalf("state after reset = %d, want StallNone", state)
+	}
+}
+
+func TestStallDetectorEscalates(t *testing.T) {
+	d := NewStallDetector(900, 3600)
+	start := time.Unix(0, 0)
+
+	d.Observe(100, start)
+	if state := d.Observe(100, start.Add(3599*time.Second)); state == StallEscalate {
+		t.Fatal("escalated before stop window")
+	}
+	if state := d.Observe(100, start.Add(3600*time.Second)); state != StallEscalate {
+		t.Fatalf("state at stop window = %d, want StallEscalate", state)
+	}
+}
+
+func TestStallDetectorDisabled(t *testing.T) {
+	d := NewStallDetector(0, 0)
+	start := time.Unix(0, 0)
+
+	d.Observe(100, start)
+	if state := d.Observe(100, start.Add(24*time.Hour)); state != StallNone {
+		t.Fatalf("state of disabled detector = %d, want StallNone", state)
+	}
+}
+
+func TestRecoverStallPolicies(t *testing.T) {
+	newExecutor := func(policy string) *Executor {
+		logger := log.NewNopLogger()
+		// Without a node binary every restart fails before a process is started.
+		return NewExecutor(&logger, &types.SupervysorConfig{
+			BinaryPath:  filepath.Join(t.TempDir(), "missing"),
+			HomePath:    t.TempDir(),
+			StallPolicy: policy,
+			Seeds:       "seed0",
+			SeedLists:   []string{"seed1", "seed2"},
+		})
+	}
+
+	for _, policy := range []string{"", "alert", "stop"} {
+		e := newExecutor(policy)
+		if err := e.RecoverStall(nil); err != nil {
+			t.Errorf("policy %q: recover stall = %s, want no restart", policy, err)
+		}
+		if e.StopOnStall() != (policy == "stop") {
+			t.Errorf("policy %q: stop on stall = %t", policy, e.StopOnStall())
+		}
+	}
+	if policy := newExecutor("").StallPolicy(); policy != StallPolicyAlert {
+		t.Errorf("default policy = %s, want %s", policy, StallPolicyAlert)
+	}
+
+	if err := newExecutor("restart").RecoverStall(nil); err == nil {
+		t.Error("policy restart: expected restart of node")
+	}
+
+	// In Ghost Mode the node is restarted without seeds, the seed list isn't rotated.
+	ghost := newExecutor("rotate-seeds")
+	ghost.Process.GhostMode = true
+	if err := ghost.RecoverStall(nil); err == nil {
+		t.Error("policy rotate-seeds in Ghost Mode: expected restart of node")
+	}
+	if ghost.seedList != 0 {
+		t.Errorf("seed list in Ghost Mode = %d, want 0", ghost.seedList)
+	}
+
+	// Every stall rotates to the next seed list, starting after the configured seeds.
+	e := newExecutor("rotate-seeds")
+	for _, want := range []int{1, 2, 0, 1} {
+		if err := e.RecoverStall(nil); err == nil {
+			t.Fatal("policy rotate-seeds: expected restart of node")
+		}
+		if e.seedList != want {
+			t.Fatalf("seed list = %d, want %d", e.seedList, want)
+		}
+	}
+}
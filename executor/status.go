@@ -0,0 +1,57 @@
+package executor
+
+import (
+	"fmt"
+
+	"github.com/KYVENetwork/supervysor/types"
+)
+
+// StatusAction defines how the supervysor reacts to the status of the KYVE pool.
+type StatusAction string
+
+const (
+	// StatusActionContinue switches modes and prunes as usual.
+	StatusActionContinue StatusAction = "continue"
+	// StatusActionSuspendPruning switches modes as usual, but doesn't prune blocks.
+	StatusActionSuspendPruning StatusAction = "suspend-pruning"
+	// StatusActionHold keeps the current mode and doesn't prune blocks.
+	StatusActionHold StatusAction = "hold"
+	// StatusActionGhost enables or keeps Ghost Mode and doesn't prune blocks.
+	StatusActionGhost StatusAction = "ghost"
+)
+
+// DefaultStatusPolicies is used for every pool status without a policy in the config. While the pool is
+// halted its height doesn't advance, so blocks aren't validated and must not be pruned. The node is kept
+// in Ghost Mode meanwhile, since in Normal Mode it would keep syncing ahead and fill up the disk.
+var DefaultStatusPolicies = map[string]StatusAction{
+	types.PoolStatusUnspecified:         StatusActionContinue,
+	types.PoolStatusActive:              StatusActionContinue,
+	types.PoolStatusDisabled:            StatusActionGhost,
+	types.PoolStatusNoFunds:             StatusActionGhost,
+	types.PoolStatusNotEnoughDelegation: StatusActionGhost,
+	types.PoolStatusUpgrading:           StatusActionGhost,
+}
+
+// ValidateStatusPolicies checks that all configured pool status policies use a known action.
+func ValidateStatusPolicies(policies map[string]string) error {
+	for status, action := range policies {
+		switch StatusAction(action) {
+		case StatusActionContinue, StatusActionSuspendPruning, StatusActionHold, StatusActionGhost:
+		default:
+			return fmt.Errorf("unknown action %s for pool status %s", action, status)
+		}
+	}
+	return nil
+}
+
+// StatusAction returns the action for the given pool status. Policies from the config take precedence
+// over the defaults, unknown statuses are handled like a halted pool.
+func (e *Executor) StatusAction(status string) StatusAction {
+	if action, ok := e.Cfg.PoolStatusPolicies[status]; ok {
+		return StatusAction(action)
+	}
+	if action, ok := DefaultStatusPolicies[status]; ok {
+		return action
+	}
+	return StatusActionGhost
+}
@@ -0,0 +1,54 @@
+package executor
+
+import (
+	"time"
+)
+
+const minVelocitySpan = 5 * time.Minute
+
+type heightSample struct {
+	height int
+	at     time.Time
+}
+
+// VelocityTracker measures the rolling velocity of a height in blocks per hour over a time window.
+type VelocityTracker struct {
+	Window time.Duration
+
+	samples []heightSample
+}
+
+func NewVelocityTracker(window time.Duration) *VelocityTracker {
+	return &VelocityTracker{Window: window}
+}
+
+// Add records a height sample and drops all samples which are older than the window.
+func (v *VelocityTracker) Add(height int, at time.Time) {
+	v.samples = append(v.samples, heightSample{height: height, at: at})
+
+	i := 0
+	for i < len(v.samples)-1 && at.Sub(v.samples[i].at) > v.Window {
+		i++
+	}
+	v.samples = v.samples[i:]
+}
+
+// Reset drops all samples, e.g. after the node was restarted in another mode.
+func (v *VelocityTracker) Reset() {
+	v.samples = nil
+}
+
+// BlocksPerHour returns the velocity over the window. It returns false if the samples don't span
+// enough time yet to be meaningful.
+func (v *VelocityTracker) BlocksPerHour() (float64, bool) {
+	if len(v.samples) < 2 {
+		return 0, false
+	}
+	first, last := v.samples[0], v.samples[len(v.samples)-1]
+
+	span := last.at.Sub(first.at)
+	if span < minVelocitySpan {
+		return 0, false
+	}
+	return float64(last.height-first.height) / span.Hours(), true
+}
@@ -0,0 +1,164 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"cosmossdk.io/log"
+	"golang.org/x/exp/slices"
+
+	"github.com/KYVENetwork/supervysor/types"
+)
+
+const (
+	EventStart        = "start"
+	EventStop         = "stop"
+	EventGhostEnable  = "ghost-enable"
+	EventNormalEnable = "normal-enable"
+	EventPrune        = "prune"
+	EventBackup       = "backup"
+
+	PhasePre  = "pre"
+	PhasePost = "post"
+
+	defaultTimeout = 60 * time.Second
+	maxOutput      = 1024
+)
+
+// Events contains all events hooks can be defined for.
+var Events = []string{EventStart, EventStop, EventGhostEnable, EventNormalEnable, EventPrune, EventBackup}
+
+// ErrAborted is returned if a fail-closed pre hook failed, the action is aborted and can be retried.
+var ErrAborted = errors.New("aborted by hook")
+
+// Env describes the event a hook is run for. Heights which are unknown are zero.
+type Env struct {
+	Mode        string
+	NodeHeight  int
+	PoolHeight  int
+	PruneHeight int
+}
+
+// Runner runs the configured shell commands before and after the actions of the supervysor.
+type Runner struct {
+	Hooks  []types.HookType
+	PoolId int
+	Home   string
+
+	logger log.Logger
+}
+
+// NewRunner creates a runner for the hooks of the config. A runner without hooks does nothing.
+func NewRunner(logger log.Logger, cfg *types.SupervysorConfig) (*Runner, error) {
+	for i, hook := range cfg.Hooks {
+		if !slices.Contains(Events, hook.Event) {
+			return nil, fmt.Errorf("invalid hook %d: unknown event %s", i, hook.Event)
+		}
+		if hook.Phase != PhasePre && hook.Phase != PhasePost {
+			return nil, fmt.Errorf("invalid hook %d: phase must be %s or %s", i, PhasePre, PhasePost)
+		}
+		if strings.TrimSpace(hook.Command) == "" {
+			return nil, fmt.Errorf("invalid hook %d: empty command", i)
+		}
+	}
+
+	return &Runner{Hooks: cfg.Hooks, PoolId: cfg.PoolId, Home: cfg.HomePath, logger: logger}, nil
+}
+
+// Run runs all hooks of the event and phase in the configured order. Failures of fail-open hooks are
+// logged, the first failure of a fail-closed hook stops the remaining hooks and is returned. Failed pre
+// hooks return ErrAborted, since the action didn't happen yet, failed post hooks are only reported.
+func (r *Runner) Run(event, phase string, env Env) error {
+	if r == nil {
+		return nil
+	}
+
+	for _, hook := range r.Hooks {
+		if hook.Event != event || hook.Phase != phase {
+			continue
+		}
+
+		if err := r.run(hook, env); err != nil {
+			if hook.FailClosed {
+				r.logger.Error("hook failed", "event", event, "phase", phase, "command", hook.Command, "err", err)
+				if phase == PhasePre {
+					return fmt.Errorf("%s-%s hook %q failed: %w: %s", phase, event, hook.Command, ErrAborted, err)
+				}
+				return fmt.Errorf("%s-%s hook %q failed: %s", phase, event, hook.Command, err)
+			}
+			r.logger.Error("hook failed, continuing", "event", event, "phase", phase, "command", hook.Command, "err", err)
+		}
+	}
+	return nil
+}
+
+// MaxDuration returns how long the hooks of the event take at most, if every hook runs into its timeout.
+func MaxDuration(hooks []types.HookType, event string) time.Duration {
+	var duration time.Duration
+	for _, hook := range hooks {
+		if hook.Event == event {
+			// The output of a killed hook is awaited for another second, see run.
+			duration += hookTimeout(hook) + time.Second
+		}
+	}
+	return duration
+}
+
+func hookTimeout(hook types.HookType) time.Duration {
+	if hook.Timeout > 0 {
+		return time.Duration(hook.Timeout) * time.Second
+	}
+	return defaultTimeout
+}
+
+func (r *Runner) run(hook types.HookType, env Env) error {
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout(hook))
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", hook.Command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", hook.Command)
+	}
+	cmd.Env = append(os.Environ(),
+		"SUPERVYSOR_EVENT="+hook.Event,
+		"SUPERVYSOR_PHASE="+hook.Phase,
+		"SUPERVYSOR_MODE="+env.Mode,
+		"SUPERVYSOR_NODE_HEIGHT="+strconv.Itoa(env.NodeHeight),
+		"SUPERVYSOR_POOL_HEIGHT="+strconv.Itoa(env.PoolHeight),
+		"SUPERVYSOR_PRUNE_HEIGHT="+strconv.Itoa(env.PruneHeight),
+		"SUPERVYSOR_POOL_ID="+strconv.Itoa(r.PoolId),
+		"SUPERVYSOR_HOME="+r.Home,
+	)
+
+	// Processes started by the command may keep the output open after it was killed.
+	cmd.WaitDelay = time.Second
+
+	start := time.Now()
+	out, err := cmd.CombinedOutput()
+	if len(out) > maxOutput {
+		out = out[len(out)-maxOutput:]
+	}
+	output := strings.TrimSpace(string(out))
+
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timed out after %s", hookTimeout(hook))
+	}
+	if err != nil {
+		if output != "" {
+			return fmt.Errorf("%s: %s", err, output)
+		}
+		return err
+	}
+
+	r.logger.Info("hook finished", "event", hook.Event, "phase", hook.Phase, "command", hook.Command, "duration", time.Since(start).String(), "output", output)
+	return nil
+}
@@ -0,0 +1,14 @@
+//go:build !unix
+
+package logging
+
+import "os"
+
+// lockFile doesn't lock the file, since file locks are only supported on unix systems.
+func lockFile(_ *os.File) error {
+	return nil
+}
+
+func unlockFile(_ *os.File) error {
+	return nil
+}
@@ -0,0 +1,43 @@
+package logging
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// PrefixWriter prefixes every line written to the underlying writer, e.g. with the mode the node
+// was started in.
+type PrefixWriter struct {
+	w      io.Writer
+	prefix []byte
+
+	mu        sync.Mutex
+	lineStart bool
+}
+
+func NewPrefixWriter(w io.Writer, prefix string) *PrefixWriter {
+	return &PrefixWriter{w: w, prefix: []byte(prefix), lineStart: true}
+}
+
+func (p *PrefixWriter) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var buf bytes.Buffer
+	for _, line := range bytes.SplitAfter(b, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		if p.lineStart {
+			buf.Write(p.prefix)
+		}
+		buf.Write(line)
+		p.lineStart = line[len(line)-1] == '\n'
+	}
+
+	if _, err := p.w.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
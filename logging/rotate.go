@@ -0,0 +1,234 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultMaxSize    = 100
+	DefaultMaxBackups = 10
+	DefaultMaxAge     = 30
+
+	// backupTimeFormat contains microseconds, so backups rotated within the same second don't collide.
+	backupTimeFormat = "20060102_150405.000000"
+)
+
+// RotatingWriter writes to a log file which is rotated once it exceeds the maximum size. Rotated files
+// are kept next to it with the rotation time in their name and are removed once there are more than
+// MaxBackups of them or they are older than MaxAge. Several processes can write to the same log file,
+// the rotation is synchronized with a lock file next to it.
+type RotatingWriter struct {
+	Path       string
+	MaxSize    int64
+	MaxBackups int
+	MaxAge     time.Duration
+
+	mu   sync.Mutex
+	file *os.File
+	lock *os.File
+	size int64
+}
+
+// NewRotatingWriter opens or creates the log file at the given path. The maximum size is given in
+// megabytes and the maximum age in days, values which are not positive fall back to the defaults.
+func NewRotatingWriter(path string, maxSize, maxBackups, maxAge int) (*RotatingWriter, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	if maxBackups <= 0 {
+		maxBackups = DefaultMaxBackups
+	}
+	if maxAge <= 0 {
+		maxAge = DefaultMaxAge
+	}
+
+	w := &RotatingWriter{
+		Path:       path,
+		MaxSize:    int64(maxSize) * 1024 * 1024,
+		MaxBackups: maxBackups,
+		MaxAge:     time.Duration(maxAge) * 24 * time.Hour,
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("could not create log directory: %w", err)
+	}
+	lock, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("could not open log lock file: %w", err)
+	}
+	w.lock = lock
+	if err := w.open(); err != nil {
+		_ = lock.Close()
+		return nil, err
+	}
+	if err := w.prune(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (w *RotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	// Other processes may append to the same file, so its actual size is used.
+	if info, err := w.file.Stat(); err == nil {
+		w.size = info.Size()
+	}
+	if w.size > 0 && w.size+int64(len(p)) > w.MaxSize {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Sync commits the current log file to disk.
+func (w *RotatingWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	return w.file.Sync()
+}
+
+func (w *RotatingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	if lockErr := w.lock.Close(); err == nil {
+		err = lockErr
+	}
+	return err
+}
+
+func (w *RotatingWriter) open() error {
+	file, err := os.OpenFile(w.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("could not open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("could not stat log file: %w", err)
+	}
+
+	w.file = file
+	w.size = info.Size()
+	return nil
+}
+
+// rotate renames the current log file to a backup, opens a new one and removes outdated backups. If
+// another process already rotated the log file, the new log file is only opened.
+func (w *RotatingWriter) rotate() error {
+	if err := lockFile(w.lock); err != nil {
+		return fmt.Errorf("could not lock log file: %w", err)
+	}
+	defer func() {
+		_ = unlockFile(w.lock)
+	}()
+
+	opened, err := w.file.Stat()
+	if err != nil {
+		return fmt.Errorf("could not stat log file: %w", err)
+	}
+	if err = w.file.Close(); err != nil {
+		return fmt.Errorf("could not close log file: %w", err)
+	}
+	w.file = nil
+
+	if current, err := os.Stat(w.Path); err == nil && os.SameFile(opened, current) {
+		if err = os.Rename(w.Path, w.backupPath()); err != nil {
+			return fmt.Errorf("could not rotate log file: %w", err)
+		}
+	}
+
+	if err = w.open(); err != nil {
+		return err
+	}
+	return w.prune()
+}
+
+// backupPath returns an unused path for a backup of the log file.
+func (w *RotatingWriter) backupPath() string {
+	ext := filepath.Ext(w.Path)
+	now := time.Now()
+	for {
+		backup := fmt.Sprintf("%s-%s%s", strings.TrimSuffix(w.Path, ext), now.Format(backupTimeFormat), ext)
+		if _, err := os.Lstat(backup); os.IsNotExist(err) {
+			return backup
+		}
+		now = now.Add(time.Microsecond)
+	}
+}
+
+func (w *RotatingWriter) prune() error {
+	ext := filepath.Ext(w.Path)
+	prefix := strings.TrimSuffix(filepath.Base(w.Path), ext) + "-"
+	return Prune(filepath.Dir(w.Path), prefix, backupTimeFormat, ext, w.MaxBackups, w.MaxAge)
+}
+
+// Prune removes the files in dir which are named by the prefix, a time in the given layout and the suffix,
+// if they are older than maxAge and, starting with the oldest, all files exceeding maxBackups. Other files
+// aren't removed, even if they share the prefix.
+func Prune(dir, prefix, layout, suffix string, maxBackups int, maxAge time.Duration) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("could not list log files: %w", err)
+	}
+
+	type logFile struct {
+		path    string
+		created time.Time
+	}
+
+	var files []logFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, suffix) {
+			continue
+		}
+		created, err := time.ParseInLocation(layout, strings.TrimSuffix(strings.TrimPrefix(name, prefix), suffix), time.Local)
+		if err != nil {
+			continue
+		}
+		files = append(files, logFile{path: filepath.Join(dir, name), created: created})
+	}
+
+	// newest first, so everything after maxBackups can be removed
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].created.After(files[j].created)
+	})
+
+	cutoff := time.Now().Add(-maxAge)
+	for i, f := range files {
+		if (maxBackups > 0 && i >= maxBackups) || (maxAge > 0 && f.created.Before(cutoff)) {
+			if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("could not remove log file: %w", err)
+			}
+		}
+	}
+	return nil
+}
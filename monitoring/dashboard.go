@@ -0,0 +1,189 @@
+package monitoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	panelWidth  = 12
+	panelHeight = 8
+
+	selector = `{instance=~"$instance"}`
+)
+
+type panel struct {
+	Type        string         `json:"type"`
+	Title       string         `json:"title"`
+	Description string         `json:"description,omitempty"`
+	GridPos     gridPos        `json:"gridPos"`
+	Datasource  datasource     `json:"datasource"`
+	Targets     []target       `json:"targets"`
+	FieldConfig map[string]any `json:"fieldConfig"`
+}
+
+type gridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type datasource struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+type target struct {
+	Expr         string     `json:"expr"`
+	LegendFormat string     `json:"legendFormat"`
+	RefID        string     `json:"refId"`
+	Datasource   datasource `json:"datasource"`
+}
+
+// overviewPanels are shown first, all other metrics get a panel of their own.
+var overviewPanels = []struct {
+	title   string
+	unit    string
+	metrics []string
+}{
+	{title: "Heights", unit: "none", metrics: []string{"supervysor_node_height", "supervysor_pool_height", "supervysor_max_height", "supervysor_min_height"}},
+	{title: "Mode", unit: "none", metrics: []string{"supervysor_mode"}},
+	{title: "Time share per mode", unit: "percentunit", metrics: []string{"supervysor_mode_seconds_total"}},
+	{title: "Data directory size", unit: "bytes", metrics: []string{"supervysor_data_dir_size"}},
+	{title: "KYVE API outage", unit: "s", metrics: []string{"supervysor_pool_outage_duration_seconds"}},
+}
+
+// Dashboard generates a Grafana dashboard with a panel for every metric.
+func Dashboard(definitions []Definition, title string) ([]byte, error) {
+	ds := datasource{Type: "prometheus", UID: "${datasource}"}
+
+	var panels []panel
+	used := make(map[string]bool)
+
+	add := func(title, description, unit string, targets []target) {
+		for i := range targets {
+			targets[i].RefID = string(rune('A' + i))
+			targets[i].Datasource = ds
+		}
+		panels = append(panels, panel{
+			Type:        "timeseries",
+			Title:       title,
+			Description: description,
+			GridPos: gridPos{
+				H: panelHeight,
+				W: panelWidth,
+				X: (len(panels) % 2) * panelWidth,
+				Y: (len(panels) / 2) * panelHeight,
+			},
+			Datasource:  ds,
+			Targets:     targets,
+			FieldConfig: map[string]any{"defaults": map[string]any{"unit": unit}, "overrides": []any{}},
+		})
+	}
+
+	for _, overview := range overviewPanels {
+		var targets []target
+		for _, name := range overview.metrics {
+			definition, ok := Lookup(definitions, name)
+			if !ok {
+				return nil, fmt.Errorf("metric %s of panel %s is not defined", name, overview.title)
+			}
+			targets = append(targets, query(definition))
+			used[name] = true
+		}
+		add(overview.title, "", overview.unit, targets)
+	}
+
+	for _, definition := range definitions {
+		if used[definition.Name] {
+			continue
+		}
+		add(panelTitle(definition), definition.Help, unitOf(definition), []target{query(definition)})
+	}
+
+	dashboard := map[string]any{
+		"title":         title,
+		"uid":           "supervysor",
+		"tags":          []string{"supervysor", "kyve"},
+		"timezone":      "browser",
+		"schemaVersion": 36,
+		"refresh":       "30s",
+		"time":          map[string]string{"from": "now-24h", "to": "now"},
+		"panels":        panels,
+		"templating": map[string]any{
+			"list": []map[string]any{
+				{
+					"name":  "datasource",
+					"label": "Data source",
+					"type":  "datasource",
+					"query": "prometheus",
+				},
+				{
+					"name":       "instance",
+					"label":      "Instance",
+					"type":       "query",
+					"datasource": ds,
+					"query":      "label_values(supervysor_node_height, instance)",
+					"refresh":    2,
+					"includeAll": true,
+					"multi":      true,
+				},
+			},
+		},
+	}
+
+	return json.MarshalIndent(dashboard, "", "  ")
+}
+
+// query returns the expression showing the metric: gauges as they are, the rate of counters and the
+// 95th percentile of histograms.
+func query(definition Definition) target {
+	legend := "{{instance}}"
+	if len(definition.Labels) > 0 {
+		var labels []string
+		for _, label := range definition.Labels {
+			labels = append(labels, "{{"+label+"}}")
+		}
+		legend = strings.Join(labels, " → ")
+	}
+
+	switch definition.Type {
+	case TypeCounter:
+		return target{Expr: fmt.Sprintf("rate(%s%s[$__rate_interval])", definition.Name, selector), LegendFormat: legend}
+	case TypeHistogram:
+		by := strings.Join(append([]string{"le"}, definition.Labels...), ", ")
+		return target{Expr: fmt.Sprintf("histogram_quantile(0.95, sum by (%s) (rate(%s_bucket%s[$__rate_interval])))", by, definition.Name, selector), LegendFormat: legend}
+	default:
+		if len(definition.Labels) == 0 {
+			legend = strings.TrimPrefix(definition.Name, "supervysor_")
+		}
+		return target{Expr: definition.Name + selector, LegendFormat: legend}
+	}
+}
+
+func panelTitle(definition Definition) string {
+	title := strings.ReplaceAll(strings.TrimPrefix(definition.Name, "supervysor_"), "_", " ")
+	switch definition.Type {
+	case TypeCounter:
+		return strings.TrimSuffix(title, " total") + " (per second)"
+	case TypeHistogram:
+		return title + " (p95)"
+	default:
+		return title
+	}
+}
+
+func unitOf(definition Definition) string {
+	switch {
+	case strings.HasSuffix(definition.Name, "_seconds"):
+		return "s"
+	case strings.HasSuffix(definition.Name, "_seconds_total"):
+		return "percentunit"
+	case strings.HasSuffix(definition.Name, "_size"):
+		return "bytes"
+	default:
+		return "none"
+	}
+}
@@ -0,0 +1,286 @@
+package monitoring
+
+import (
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	TypeGauge     = "gauge"
+	TypeCounter   = "counter"
+	TypeHistogram = "histogram"
+)
+
+// Definition describes a metric exposed by the supervysor. The collectors of types.Metrics are created from
+// the definitions, so dashboards and alerting rules always match the exposed metrics.
+type Definition struct {
+	Name    string
+	Help    string
+	Type    string
+	Labels  []string
+	Buckets []float64
+}
+
+var (
+	PoolHeight = Definition{
+		Name: "supervysor_pool_height",
+		Help: "Height of the specified KYVE data pool.",
+		Type: TypeGauge,
+	}
+	NodeHeight = Definition{
+		Name: "supervysor_node_height",
+		Help: "Height of the running data source node.",
+		Type: TypeGauge,
+	}
+	MaxHeight = Definition{
+		Name: "supervysor_max_height",
+		Help: "Maximum height of node until Ghost Mode enabling.",
+		Type: TypeGauge,
+	}
+	MinHeight = Definition{
+		Name: "supervysor_min_height",
+		Help: "Minimum height of node until Normal Mode enabling.",
+		Type: TypeGauge,
+	}
+	DataDirSize = Definition{
+		Name: "supervysor_data_dir_size",
+		Help: "Size of data dir in --home dir.",
+		Type: TypeGauge,
+	}
+	PoolOutageDuration = Definition{
+		Name: "supervysor_pool_outage_duration_seconds",
+		Help: "Duration of the ongoing KYVE API outage (0 if the API is reachable).",
+		Type: TypeGauge,
+	}
+	PoolStatus = Definition{
+		Name:   "supervysor_pool_status",
+		Help:   "Set to 1 for the current status of the KYVE pool.",
+		Type:   TypeGauge,
+		Labels: []string{"status"},
+	}
+	PoolEventsConnected = Definition{
+		Name: "supervysor_pool_events_connected",
+		Help: "Set to 1 if the subscription to finalized bundles of the KYVE pool is active.",
+		Type: TypeGauge,
+	}
+	PoolVelocity = Definition{
+		Name: "supervysor_pool_velocity",
+		Help: "Rolling velocity of the KYVE pool in blocks per hour.",
+		Type: TypeGauge,
+	}
+	NodeVelocity = Definition{
+		Name: "supervysor_node_velocity",
+		Help: "Rolling sync velocity of the node in Normal Mode in blocks per hour.",
+		Type: TypeGauge,
+	}
+	CatchUpTime = Definition{
+		Name: "supervysor_catch_up_time_seconds",
+		Help: "Measured time the node needs after enabling Normal Mode until it syncs again.",
+		Type: TypeGauge,
+	}
+	GhostModeLeak = Definition{
+		Name: "supervysor_ghost_mode_leak",
+		Help: "Set to 1 if the node kept syncing after the last switch to Ghost Mode.",
+		Type: TypeGauge,
+	}
+	GhostModeLeaks = Definition{
+		Name: "supervysor_ghost_mode_leaks_total",
+		Help: "Number of switches to Ghost Mode after which the node kept syncing.",
+		Type: TypeCounter,
+	}
+	GhostIsolationUnverified = Definition{
+		Name: "supervysor_ghost_isolation_unverified_total",
+		Help: "Number of switches to Ghost Mode after which the peers of the node could not be queried.",
+		Type: TypeCounter,
+	}
+	PoolEndpointHeight = Definition{
+		Name:   "supervysor_pool_endpoint_height",
+		Help:   "Pool height reported by each KYVE endpoint.",
+		Type:   TypeGauge,
+		Labels: []string{"endpoint"},
+	}
+	PoolEndpointLatency = Definition{
+		Name:   "supervysor_pool_endpoint_latency_seconds",
+		Help:   "Latency of the last pool request to each KYVE endpoint.",
+		Type:   TypeGauge,
+		Labels: []string{"endpoint"},
+	}
+	PoolEndpointOutlier = Definition{
+		Name:   "supervysor_pool_endpoint_outlier",
+		Help:   "Set to 1 if the KYVE endpoint disagrees with the median pool height beyond the tolerance.",
+		Type:   TypeGauge,
+		Labels: []string{"endpoint"},
+	}
+	PoolEndpointErrors = Definition{
+		Name:   "supervysor_pool_endpoint_errors_total",
+		Help:   "Number of failed pool requests to each KYVE endpoint.",
+		Type:   TypeCounter,
+		Labels: []string{"endpoint"},
+	}
+	CurrentMode = Definition{
+		Name:   "supervysor_mode",
+		Help:   "Set to 1 for the current mode of the node.",
+		Type:   TypeGauge,
+		Labels: []string{"mode"},
+	}
+	ModeSeconds = Definition{
+		Name:   "supervysor_mode_seconds_total",
+		Help:   "Time the node spent in each mode.",
+		Type:   TypeCounter,
+		Labels: []string{"mode"},
+	}
+	ModeTransitions = Definition{
+		Name:   "supervysor_mode_transitions_total",
+		Help:   "Number of switches between the modes.",
+		Type:   TypeCounter,
+		Labels: []string{"from", "to"},
+	}
+	SwitchDowntime = Definition{
+		Name:    "supervysor_switch_downtime_seconds",
+		Help:    "Time the RPC of the node was unavailable during a mode switch.",
+		Type:    TypeHistogram,
+		Labels:  []string{"to"},
+		Buckets: prometheus.ExponentialBuckets(1, 2, 10),
+	}
+	PruneRuns = Definition{
+		Name: "supervysor_prune_runs_total",
+		Help: "Number of block prunings.",
+		Type: TypeCounter,
+	}
+	PrunedBlocks = Definition{
+		Name: "supervysor_pruned_blocks_total",
+		Help: "Number of pruned blocks.",
+		Type: TypeCounter,
+	}
+	PruneDuration = Definition{
+		Name:    "supervysor_prune_duration_seconds",
+		Help:    "Duration of the block pruning including the restart of the node.",
+		Type:    TypeHistogram,
+		Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+	}
+	BlockstoreBase = Definition{
+		Name: "supervysor_blockstore_base_height",
+		Help: "Lowest height stored in the blockstore of the node.",
+		Type: TypeGauge,
+	}
+	NodeRestarts = Definition{
+		Name: "supervysor_node_restarts_total",
+		Help: "Number of restarts of the node process.",
+		Type: TypeCounter,
+	}
+	NodeExits = Definition{
+		Name:   "supervysor_node_exits_total",
+		Help:   "Number of exits of the node process by exit code (-1 if terminated by a signal).",
+		Type:   TypeCounter,
+		Labels: []string{"exit_code"},
+	}
+	NodeStall = Definition{
+		Name: "supervysor_node_stall_seconds",
+		Help: "Time the height of the node in Normal Mode didn't advance.",
+		Type: TypeGauge,
+	}
+	NodeStalls = Definition{
+		Name:   "supervysor_node_stalls_total",
+		Help:   "Number of detected stalls of the node in Normal Mode by the applied policy.",
+		Type:   TypeCounter,
+		Labels: []string{"policy"},
+	}
+	ProxyRequests = Definition{
+		Name:   "supervysor_proxy_requests_total",
+		Help:   "Number of requests forwarded to the node by endpoint and status code.",
+		Type:   TypeCounter,
+		Labels: []string{"proxy", "endpoint", "code"},
+	}
+	ProxyRequestDuration = Definition{
+		Name:    "supervysor_proxy_request_duration_seconds",
+		Help:    "Duration of the requests forwarded to the node including the time they were held during restarts.",
+		Type:    TypeHistogram,
+		Labels:  []string{"proxy", "endpoint"},
+		Buckets: prometheus.ExponentialBuckets(0.005, 4, 8),
+	}
+	ProxyRetries = Definition{
+		Name:   "supervysor_proxy_retries_total",
+		Help:   "Number of requests sent again because the node didn't accept the connection.",
+		Type:   TypeCounter,
+		Labels: []string{"proxy"},
+	}
+)
+
+// Definitions returns the definitions of all metrics sorted by name.
+func Definitions() []Definition {
+	definitions := []Definition{
+		PoolHeight,
+		NodeHeight,
+		MaxHeight,
+		MinHeight,
+		DataDirSize,
+		PoolOutageDuration,
+		PoolStatus,
+		PoolEventsConnected,
+		PoolVelocity,
+		NodeVelocity,
+		CatchUpTime,
+		GhostModeLeak,
+		GhostModeLeaks,
+		GhostIsolationUnverified,
+		PoolEndpointHeight,
+		PoolEndpointLatency,
+		PoolEndpointOutlier,
+		PoolEndpointErrors,
+		CurrentMode,
+		ModeSeconds,
+		ModeTransitions,
+		SwitchDowntime,
+		PruneRuns,
+		PrunedBlocks,
+		PruneDuration,
+		BlockstoreBase,
+		NodeRestarts,
+		NodeExits,
+		NodeStall,
+		NodeStalls,
+		ProxyRequests,
+		ProxyRequestDuration,
+		ProxyRetries,
+	}
+
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Name < definitions[j].Name
+	})
+	return definitions
+}
+
+// Lookup returns the definition of the metric with the given name.
+func Lookup(definitions []Definition, name string) (Definition, bool) {
+	for _, d := range definitions {
+		if d.Name == name {
+			return d, true
+		}
+	}
+	return Definition{}, false
+}
+
+func (d Definition) NewGauge() prometheus.Gauge {
+	return prometheus.NewGauge(prometheus.GaugeOpts{Name: d.Name, Help: d.Help})
+}
+
+func (d Definition) NewGaugeVec() *prometheus.GaugeVec {
+	return prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: d.Name, Help: d.Help}, d.Labels)
+}
+
+func (d Definition) NewCounter() prometheus.Counter {
+	return prometheus.NewCounter(prometheus.CounterOpts{Name: d.Name, Help: d.Help})
+}
+
+func (d Definition) NewCounterVec() *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{Name: d.Name, Help: d.Help}, d.Labels)
+}
+
+func (d Definition) NewHistogram() prometheus.Histogram {
+	return prometheus.NewHistogram(prometheus.HistogramOpts{Name: d.Name, Help: d.Help, Buckets: d.Buckets})
+}
+
+func (d Definition) NewHistogramVec() *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: d.Name, Help: d.Help, Buckets: d.Buckets}, d.Labels)
+}
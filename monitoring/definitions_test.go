@@ -0,0 +1,102 @@
+package monitoring_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/KYVENetwork/supervysor/cmd/supervysor/helpers"
+	"github.com/KYVENetwork/supervysor/monitoring"
+)
+
+// recordingRegistry records the collectors registered by NewMetrics.
+type recordingRegistry struct {
+	*prometheus.Registry
+	collectors []prometheus.Collector
+}
+
+func (r *recordingRegistry) MustRegister(collectors ...prometheus.Collector) {
+	r.Registry.MustRegister(collectors...)
+	r.collectors = append(r.collectors, collectors...)
+}
+
+func typeOf(collector prometheus.Collector) string {
+	switch collector.(type) {
+	// Gauges also implement the Counter interface, which is why they need to be matched first.
+	case prometheus.Histogram, *prometheus.HistogramVec:
+		return monitoring.TypeHistogram
+	case prometheus.Gauge, *prometheus.GaugeVec:
+		return monitoring.TypeGauge
+	case prometheus.Counter, *prometheus.CounterVec:
+		return monitoring.TypeCounter
+	}
+	return ""
+}
+
+// registeredDefinitions returns the definition of every registered metric, matching the descriptions of the
+// collectors against descriptions built from the definitions.
+func registeredDefinitions(t *testing.T) []monitoring.Definition {
+	t.Helper()
+
+	reg := &recordingRegistry{Registry: prometheus.NewRegistry()}
+	helpers.NewMetrics(reg)
+
+	definitions := monitoring.Definitions()
+	var registered []monitoring.Definition
+	for _, collector := range reg.collectors {
+		ch := make(chan *prometheus.Desc, 1)
+		collector.Describe(ch)
+		close(ch)
+
+		for desc := range ch {
+			var found bool
+			for _, d := range definitions {
+				if prometheus.NewDesc(d.Name, d.Help, d.Labels, nil).String() != desc.String() {
+					continue
+				}
+				if typeOf(collector) != d.Type {
+					t.Errorf("metric %s is a %s, defined as %s", d.Name, typeOf(collector), d.Type)
+				}
+				registered = append(registered, d)
+				found = true
+			}
+			if !found {
+				t.Errorf("registered metric %s is not defined", desc)
+			}
+		}
+	}
+
+	if len(registered) != len(definitions) {
+		t.Errorf("%d metrics registered, %d defined", len(registered), len(definitions))
+	}
+	return registered
+}
+
+func TestDashboardContainsRegisteredMetrics(t *testing.T) {
+	dashboard, err := monitoring.Dashboard(monitoring.Definitions(), "Supervysor")
+	if err != nil {
+		t.Fatalf("could not generate dashboard: %s", err)
+	}
+
+	for _, d := range registeredDefinitions(t) {
+		if !strings.Contains(string(dashboard), d.Name) {
+			t.Errorf("metric %s is missing in the dashboard", d.Name)
+		}
+	}
+}
+
+func TestRulesUseRegisteredMetrics(t *testing.T) {
+	registered := registeredDefinitions(t)
+
+	// Generating the rules fails if a metric of an alert isn't passed.
+	rules, err := monitoring.Rules(registered, monitoring.RuleOptions{DiskSize: 1e12, OutageGrace: 300})
+	if err != nil {
+		t.Fatalf("could not generate rules from registered metrics: %s", err)
+	}
+	for _, name := range []string{"supervysor_node_height", "supervysor_ghost_mode_leak", "supervysor_data_dir_size", "supervysor_pool_outage_duration_seconds"} {
+		if !strings.Contains(string(rules), name) {
+			t.Errorf("metric %s is missing in the rules", name)
+		}
+	}
+}
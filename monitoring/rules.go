@@ -0,0 +1,101 @@
+package monitoring
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+type ruleFile struct {
+	Groups []ruleGroup `yaml:"groups"`
+}
+
+type ruleGroup struct {
+	Name  string `yaml:"name"`
+	Rules []rule `yaml:"rules"`
+}
+
+type rule struct {
+	Alert       string            `yaml:"alert"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels"`
+	Annotations map[string]string `yaml:"annotations"`
+
+	// metrics are the metrics the expression is based on, which need to be defined.
+	metrics []string
+}
+
+// RuleOptions configures the thresholds of the generated alerting rules.
+type RuleOptions struct {
+	// DiskSize is the size of the volume of the data directory in bytes.
+	DiskSize float64
+	// OutageGrace is the number of seconds the KYVE API can be unreachable without an alert.
+	OutageGrace int
+}
+
+// Rules generates Prometheus alerting rules for the metrics of the supervysor.
+func Rules(definitions []Definition, opts RuleOptions) ([]byte, error) {
+	rules := []rule{
+		{
+			Alert: "SupervysorNodeBehindPool",
+			Expr:  "supervysor_node_height < supervysor_pool_height",
+			For:   "30m",
+			Labels: map[string]string{
+				"severity": "critical",
+			},
+			Annotations: map[string]string{
+				"summary":     "Node is behind the KYVE pool",
+				"description": "The node of {{ $labels.instance }} is at height {{ $value }} below the pool height and can't be used as data source.",
+			},
+			metrics: []string{"supervysor_node_height", "supervysor_pool_height"},
+		},
+		{
+			Alert: "SupervysorGhostModeLeak",
+			Expr:  "supervysor_ghost_mode_leak == 1",
+			Labels: map[string]string{
+				"severity": "critical",
+			},
+			Annotations: map[string]string{
+				"summary":     "Node keeps syncing in Ghost Mode",
+				"description": "The node of {{ $labels.instance }} kept syncing after Ghost Mode was enabled, so the disk keeps filling up.",
+			},
+			metrics: []string{"supervysor_ghost_mode_leak"},
+		},
+		{
+			Alert: "SupervysorDiskNearlyFull",
+			Expr:  fmt.Sprintf("supervysor_data_dir_size > %g", opts.DiskSize*0.9),
+			For:   "10m",
+			Labels: map[string]string{
+				"severity": "warning",
+			},
+			Annotations: map[string]string{
+				"summary":     "Data directory of the node is nearly full",
+				"description": "The data directory of {{ $labels.instance }} uses {{ $value | humanize1024 }}B, more than 90% of the disk.",
+			},
+			metrics: []string{"supervysor_data_dir_size"},
+		},
+		{
+			Alert: "SupervysorKYVEAPIUnreachable",
+			Expr:  fmt.Sprintf("supervysor_pool_outage_duration_seconds > %d", opts.OutageGrace),
+			Labels: map[string]string{
+				"severity": "warning",
+			},
+			Annotations: map[string]string{
+				"summary":     "KYVE API is unreachable",
+				"description": "{{ $labels.instance }} could not reach any KYVE endpoint for {{ $value | humanizeDuration }}, the mode of the node isn't switched meanwhile.",
+			},
+			metrics: []string{"supervysor_pool_outage_duration_seconds"},
+		},
+	}
+
+	for _, r := range rules {
+		for _, name := range r.metrics {
+			if _, ok := Lookup(definitions, name); !ok {
+				return nil, fmt.Errorf("metric %s of alert %s is not defined", name, r.Alert)
+			}
+		}
+	}
+
+	return yaml.Marshal(ruleFile{Groups: []ruleGroup{{Name: "supervysor", Rules: rules}}})
+}
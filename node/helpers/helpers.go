@@ -6,8 +6,13 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"cosmossdk.io/log"
+	"github.com/spf13/viper"
+
+	"github.com/KYVENetwork/supervysor/types"
+	cfg "github.com/tendermint/tendermint/config"
 )
 
 // GetPort resolves an unused TCP address.
@@ -133,3 +138,54 @@ func MoveAddressBook(activateGhostMode bool, addrBookPath string, log log.Logger
 
 	return nil
 }
+
+// GetGhostPeerArgs returns the command line arguments which prevent the node from connecting to any peer in
+// Ghost Mode. Besides the seeds and the address book, a node can also connect to its persistent peers,
+// unconditional peers and peers it learns through PEX, so all of them are overwritten. The config is only
+// used to log which settings are neutralised and can be nil.
+func GetGhostPeerArgs(config *types.Config, log log.Logger) []string {
+	if config != nil {
+		if strings.TrimSpace(config.P2P.PersistentPeers) != "" {
+			log.Info("neutralising persistent peers in Ghost Mode", "persistent-peers", len(strings.Split(config.P2P.PersistentPeers, ",")))
+		}
+		if strings.TrimSpace(config.P2P.UnconditionalPeerIDs) != "" {
+			log.Info("neutralising unconditional peers in Ghost Mode", "unconditional-peers", len(strings.Split(config.P2P.UnconditionalPeerIDs, ",")))
+		}
+		if strings.TrimSpace(config.P2P.PrivatePeerIDs) != "" {
+			log.Info("neutralising private peers in Ghost Mode", "private-peers", len(strings.Split(config.P2P.PrivatePeerIDs, ",")))
+		}
+		if config.P2P.PexReactor {
+			log.Info("disabling PEX in Ghost Mode")
+		}
+	}
+
+	return []string{
+		"--p2p.seeds", " ",
+		"--p2p.persistent_peers", " ",
+		"--p2p.unconditional_peer_ids", " ",
+		"--p2p.private_peer_ids", " ",
+		"--p2p.pex=false",
+	}
+}
+
+// LoadConfig loads the Tendermint config of the node from its home directory.
+func LoadConfig(homeDir string) (config *cfg.Config, err error) {
+	config = cfg.DefaultConfig()
+
+	viper.SetConfigName("config")
+	viper.SetConfigType("toml")
+	viper.AddConfigPath(homeDir)
+	viper.AddConfigPath(filepath.Join(homeDir, "config"))
+
+	if err := viper.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	if err := viper.Unmarshal(config); err != nil {
+		return nil, err
+	}
+
+	config.SetRoot(homeDir)
+
+	return config, nil
+}
@@ -1,12 +1,11 @@
 package node
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"math"
-	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -17,51 +16,68 @@ import (
 
 	"cosmossdk.io/log"
 
+	"github.com/KYVENetwork/supervysor/logging"
 	"github.com/KYVENetwork/supervysor/node/helpers"
 	"github.com/KYVENetwork/supervysor/types"
 )
 
-// The GetNodeHeight function retrieves the height of the node by querying the ABCI endpoint.
-// It uses exponential backoff
-func GetNodeHeight(log log.Logger, p *types.ProcessType, abciEndpoint string) (int, error) {
+const (
+	// maxBackoff caps the delay between the retries of the node status query.
+	maxBackoff = time.Minute
+	// maxInvalidRetries is how often an invalid response is retried, e.g. while the app isn't ready yet.
+	maxInvalidRetries = 3
+)
+
+// GetNodeStatus queries the status of the node. While the node process hasn't started yet or its RPC isn't
+// reachable, responding or closes the connection, the query is retried with exponential backoff. Invalid
+// responses are only retried maxInvalidRetries times. The returned error wraps ErrNodeStarting,
+// ErrNodeUnresponsive or ErrInvalidResponse if it could be classified.
+func GetNodeStatus(ctx context.Context, log log.Logger, p *types.ProcessType, abciEndpoint string) (Status, error) {
+	client := NewStatusClient(abciEndpoint)
+
+	var err error
+	invalid := 0
 	for i := 0; i <= types.BackoffMaxRetries; i++ {
 		delay := time.Duration(math.Pow(2, float64(i))) * time.Second
-		if p.Id == -1 {
-			log.Error(fmt.Sprintf("node hasn't started yet. Try again in %vs ...", delay))
-
-			time.Sleep(delay)
-			continue
+		if delay > maxBackoff {
+			delay = maxBackoff
 		}
 
-		response, err := http.Get(abciEndpoint + "/abci_info?")
-
-		if err != nil {
-			log.Error(fmt.Sprintf("failed to query height. Try again in %vs ...", delay))
-
-			time.Sleep(delay)
-			continue
-		} else {
-			responseData, err := io.ReadAll(response.Body)
-			if err != nil {
-				log.Error("could not read response data", "err", err.Error())
-			}
+		if p.Id == -1 {
+			err = fmt.Errorf("%w: node hasn't started yet", ErrNodeStarting)
+			log.Error(fmt.Sprintf("node hasn't started yet. Try again in %s ...", delay))
 
-			var resp types.HeightResponse
-			err = json.Unmarshal(responseData, &resp)
-			if err != nil {
-				log.Error("could not unmarshal JSON", "err", err.Error())
+			select {
+			case <-ctx.Done():
+				return Status{}, ctx.Err()
+			case <-time.After(delay):
 			}
+			continue
+		}
 
-			lastBlockHeight := resp.Result.Response.LastBlockHeight
-			nodeHeight, err := strconv.Atoi(lastBlockHeight)
-			if err != nil {
-				log.Error("could not convert lastBlockHeight to str", "err", err.Error())
+		var status Status
+		status, err = client.Status(ctx)
+		if err == nil {
+			return status, nil
+		}
+		if errors.Is(err, ErrInvalidResponse) {
+			invalid++
+			if invalid > maxInvalidRetries {
+				return Status{}, fmt.Errorf("could not query node status: %w", err)
 			}
+		}
+		if ctx.Err() != nil {
+			return Status{}, ctx.Err()
+		}
 
-			return nodeHeight, nil
+		log.Error(fmt.Sprintf("failed to query node status. Try again in %s ...", delay), "err", err)
+		select {
+		case <-ctx.Done():
+			return Status{}, ctx.Err()
+		case <-time.After(delay):
 		}
 	}
-	return 0, fmt.Errorf("could not query node height")
+	return Status{}, fmt.Errorf("could not query node status: %w", err)
 }
 
 // StartNode starts the node process in Normal Mode and returns the os.Process object representing
@@ -112,38 +128,12 @@ func StartNode(cfg *types.SupervysorConfig, log log.Logger, p *types.ProcessType
 		args = append(args, flags...)
 
 		cmd := exec.Command(cmdPath, args...)
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-
-		processIDChan := make(chan int)
-
-		go func() {
-			err = cmd.Start()
-			if err != nil {
-				log.Error("could not start Normal Mode process", "err", err)
-				processIDChan <- -1
-				return
-			}
-
-			processIDChan <- cmd.Process.Pid
-
-			// Wait for process end
-			err = cmd.Wait()
-			if err != nil {
-				// Process can only be stopped through an error, not necessary to log an error
-				processIDChan <- -1
-			}
-		}()
+		setOutput(cmd, cfg, p, "normal")
 
-		processID := <-processIDChan
-
-		if processID == -1 {
-			return nil, errors.New("couldn't start running the node")
-		}
-
-		process, err := os.FindProcess(processID)
+		process, err := startProcess(cmd)
 		if err != nil {
-			return nil, fmt.Errorf("could not find started process: %s", err)
+			log.Error("could not start Normal Mode process", "err", err)
+			return nil, errors.New("couldn't start running the node")
 		}
 
 		return process, nil
@@ -153,8 +143,10 @@ func StartNode(cfg *types.SupervysorConfig, log log.Logger, p *types.ProcessType
 // StartGhostNode starts the node process in Ghost Mode and returns the os.Process object
 // representing the running process. It moves the address book, checks if the node is already running
 // or in Ghost Mode ands sets the appropriate command arguments based on the binaryPath.
-// It starts the node without seeds and with a changed laddr, so the node can't continue syncing.
-func StartGhostNode(cfg *types.SupervysorConfig, log log.Logger, p *types.ProcessType, restart bool, flags []string) (*os.Process, error) {
+// It starts the node without seeds, persistent or unconditional peers, with PEX disabled and with a changed
+// laddr, so the node can't continue syncing. In strict mode the laddr is bound to the loopback interface,
+// so no peer can connect to the node from outside.
+func StartGhostNode(cfg *types.SupervysorConfig, log log.Logger, p *types.ProcessType, restart bool, strict bool, flags []string) (*os.Process, error) {
 	addrBookPath := filepath.Join(cfg.HomePath, "config", "addrbook.json")
 
 	if err := helpers.MoveAddressBook(true, addrBookPath, log); err != nil {
@@ -179,14 +171,21 @@ func StartGhostNode(cfg *types.SupervysorConfig, log log.Logger, p *types.Proces
 		}
 
 		laddr := "tcp://0.0.0.0:" + strconv.Itoa(port)
+		if strict {
+			laddr = "tcp://127.0.0.1:" + strconv.Itoa(port)
+		}
+
+		config, err := helpers.LoadConfig(cfg.HomePath)
+		if err != nil {
+			log.Error("could not load node config, overwriting all peer settings anyway", "err", err)
+		}
 
 		args := []string{
 			"start",
-			"--p2p.seeds",
-			" ",
 			"--p2p.laddr",
 			laddr,
 		}
+		args = append(args, helpers.GetGhostPeerArgs(config, log)...)
 
 		if strings.HasSuffix(cfg.BinaryPath, "/cosmovisor") {
 			args = append([]string{"run"}, args...)
@@ -199,58 +198,86 @@ func StartGhostNode(cfg *types.SupervysorConfig, log log.Logger, p *types.Proces
 		args = append(args, flags...)
 
 		cmd := exec.Command(cmdPath, args...)
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-
-		processIDChan := make(chan int)
-
-		go func() {
-			err = cmd.Start()
-			if err != nil {
-				log.Error("could not start Ghost Node process", "err", err)
-				processIDChan <- -1
-				return
-			}
-
-			processIDChan <- cmd.Process.Pid
-
-			err = cmd.Wait()
-			if err != nil {
-				// Process can only be stopped through an error, which is why we don't need to log it
-				processIDChan <- -1
-			}
-		}()
+		setOutput(cmd, cfg, p, "ghost")
 
-		processID := <-processIDChan
-
-		if processID == -1 {
-			return nil, fmt.Errorf("could not start running the node")
-		}
-
-		process, err := os.FindProcess(processID)
+		process, err := startProcess(cmd)
 		if err != nil {
-			return nil, fmt.Errorf("could not find started process: %s", err)
+			log.Error("could not start Ghost Node process", "err", err)
+			return nil, fmt.Errorf("could not start running the node")
 		}
 
 		return process, nil
 	}
 }
 
-func ShutdownNode(p *types.ProcessType) error {
+// ShutdownNode terminates the node process with SIGTERM and waits until it exited. If the process doesn't
+// exit within the shutdown timeout, the whole process group is killed with SIGKILL. Afterwards it waits
+// until the database locks are released, so the next node process or the pruning can open them.
+func ShutdownNode(cfg *types.SupervysorConfig, log log.Logger, p *types.ProcessType) error {
 	if p.Id != -1 {
+		timeout := shutdownTimeout(cfg)
+
 		process, err := os.FindProcess(p.Id)
 		if err != nil {
 			return fmt.Errorf("could not find process to shutdown: %s", err)
 		}
 
-		if err = process.Signal(syscall.SIGTERM); err != nil {
+		start := time.Now()
+		if err = process.Signal(syscall.SIGTERM); err != nil && !errors.Is(err, os.ErrProcessDone) {
 			return fmt.Errorf("could not terminate process: %s", err)
 		}
 
-		time.Sleep(time.Second * time.Duration(20))
+		if !waitForExit(p.Id, timeout) {
+			log.Error("node did not exit in time, killing process group", "pId", p.Id, "timeout", timeout.String())
 
+			if err = killProcessGroup(p.Id); err != nil {
+				return fmt.Errorf("could not kill process group: %s", err)
+			}
+			if !waitForExit(p.Id, killTimeout) {
+				return fmt.Errorf("process %d did not exit after SIGKILL", p.Id)
+			}
+		}
+		p.ExitCode = ExitCode(p.Id)
+		log.Info("node process exited", "pId", p.Id, "exit-code", p.ExitCode, "duration", time.Since(start).String())
+
+		forget(p.Id)
 		p.Id = -1
+
+		if err = WaitForLockRelease(cfg.HomePath, timeout); err != nil {
+			return fmt.Errorf("database locks were not released: %s", err)
+		}
 	}
 
 	return nil
 }
+
+// MaxShutdownDuration returns how long ShutdownNode takes at most: the shutdown timeout until the process
+// group is killed, the wait for the killed process and the shutdown timeout until the locks are released.
+func MaxShutdownDuration(cfg *types.SupervysorConfig) time.Duration {
+	return 2*shutdownTimeout(cfg) + killTimeout
+}
+
+func shutdownTimeout(cfg *types.SupervysorConfig) time.Duration {
+	if cfg.ShutdownTimeout > 0 {
+		return time.Duration(cfg.ShutdownTimeout) * time.Second
+	}
+	return defaultShutdownTimeout
+}
+
+// setOutput passes the output of the node process to the output of the process type, tagged with the
+// mode if configured, or to the supervysor's own output if none is set.
+func setOutput(cmd *exec.Cmd, cfg *types.SupervysorConfig, p *types.ProcessType, mode string) {
+	if p.Output == nil {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return
+	}
+
+	var output io.Writer = p.Output
+	if cfg.NodeLogsModeTag {
+		output = logging.NewPrefixWriter(output, "["+mode+"] ")
+	}
+	// The same writer for both streams makes exec copy them through a single pipe, so lines don't interleave.
+	cmd.Stdout = output
+	cmd.Stderr = output
+}
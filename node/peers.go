@@ -0,0 +1,104 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const rpcTimeout = 10 * time.Second
+
+// Peer is a peer the node is connected to, as returned by /net_info.
+type Peer struct {
+	Id         string
+	RemoteIP   string
+	ListenAddr string
+}
+
+type netInfoResponse struct {
+	Result struct {
+		Peers []struct {
+			NodeInfo struct {
+				Id         string `json:"id"`
+				ListenAddr string `json:"listen_addr"`
+			} `json:"node_info"`
+			RemoteIP string `json:"remote_ip"`
+		} `json:"peers"`
+	} `json:"result"`
+}
+
+type rpcErrorResponse struct {
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    string `json:"data"`
+	} `json:"error"`
+}
+
+// PeerManager queries the p2p connections of a running node through its RPC, which is used to verify that
+// the node has no peers in Ghost Mode. It can't isolate the node: the RPC of Tendermint 0.34 has no routes
+// to disconnect peers or stop dialing, so Ghost Mode is always enabled by restarting the node.
+type PeerManager struct {
+	Endpoint string
+
+	httpClient *http.Client
+}
+
+func NewPeerManager(endpoint string) *PeerManager {
+	return &PeerManager{
+		Endpoint:   strings.TrimSuffix(endpoint, "/"),
+		httpClient: &http.Client{Timeout: rpcTimeout},
+	}
+}
+
+// Peers returns all peers the node is currently connected to.
+func (m *PeerManager) Peers(ctx context.Context) ([]Peer, error) {
+	body, err := m.get(ctx, "net_info")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp netInfoResponse
+	if err = json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("could not unmarshal net_info: %w", err)
+	}
+
+	peers := make([]Peer, 0, len(resp.Result.Peers))
+	for _, p := range resp.Result.Peers {
+		peers = append(peers, Peer{Id: p.NodeInfo.Id, RemoteIP: p.RemoteIP, ListenAddr: p.NodeInfo.ListenAddr})
+	}
+	return peers, nil
+}
+
+// get calls a route of the RPC and returns the response body. JSON-RPC errors are returned as error.
+func (m *PeerManager) get(ctx context.Context, route string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.Endpoint+"/"+route, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := m.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var rpcErr rpcErrorResponse
+	if json.Unmarshal(body, &rpcErr) == nil && rpcErr.Error != nil {
+		return nil, fmt.Errorf("%s: %s %s", route, rpcErr.Error.Message, rpcErr.Error.Data)
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s responded with status %d", route, response.StatusCode)
+	}
+
+	return body, nil
+}
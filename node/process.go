@@ -0,0 +1,140 @@
+package node
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	defaultShutdownTimeout = 60 * time.Second
+	killTimeout            = 10 * time.Second
+	pollInterval           = 200 * time.Millisecond
+)
+
+// child is a node process started by the supervysor. Its exit is observed by waiting on the command,
+// so shutdowns don't need to rely on fixed sleeps.
+type child struct {
+	cmd  *exec.Cmd
+	done chan struct{}
+	err  error
+}
+
+var (
+	childrenMu sync.Mutex
+	children   = make(map[int]*child)
+)
+
+// startProcess starts the command in its own process group, so the whole group (e.g. cosmovisor and
+// the daemon it runs) can be killed, and keeps track of it until it exits.
+func startProcess(cmd *exec.Cmd) (*os.Process, error) {
+	setProcessGroup(cmd)
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	c := &child{cmd: cmd, done: make(chan struct{})}
+
+	childrenMu.Lock()
+	children[cmd.Process.Pid] = c
+	childrenMu.Unlock()
+
+	go func() {
+		// Process can only be stopped through an error, which is why it is stored and not logged
+		c.err = cmd.Wait()
+		close(c.done)
+	}()
+
+	return cmd.Process, nil
+}
+
+// Exited returns a channel which is closed once the process with the given ID exited. It returns nil
+// if the process wasn't started by the supervysor.
+func Exited(pid int) <-chan struct{} {
+	childrenMu.Lock()
+	defer childrenMu.Unlock()
+
+	if c, ok := children[pid]; ok {
+		return c.done
+	}
+	return nil
+}
+
+// ExitCode returns the exit code of an exited process started by the supervysor, or -1 if the process
+// is unknown, still running or was terminated by a signal.
+func ExitCode(pid int) int {
+	childrenMu.Lock()
+	c, ok := children[pid]
+	childrenMu.Unlock()
+
+	if !ok {
+		return -1
+	}
+	select {
+	case <-c.done:
+		return c.cmd.ProcessState.ExitCode()
+	default:
+		return -1
+	}
+}
+
+// waitForExit waits until the process exited or the timeout passed and reports whether it exited.
+func waitForExit(pid int, timeout time.Duration) bool {
+	if done := Exited(pid); done != nil {
+		select {
+		case <-done:
+			return true
+		case <-time.After(timeout):
+			return false
+		}
+	}
+
+	// Processes which weren't started by the supervysor are polled.
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if !processAlive(pid) {
+			return true
+		}
+		time.Sleep(pollInterval)
+	}
+	return !processAlive(pid)
+}
+
+// forget removes an exited process from the tracked processes.
+func forget(pid int) {
+	childrenMu.Lock()
+	defer childrenMu.Unlock()
+
+	delete(children, pid)
+}
+
+// WaitForLockRelease waits until no process holds a lock on any database in the data directory of the
+// node anymore, which guarantees that the next node process or the pruning can open the databases.
+func WaitForLockRelease(homePath string, timeout time.Duration) error {
+	lockFiles, err := filepath.Glob(filepath.Join(homePath, "data", "*.db", "LOCK"))
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for _, lockFile := range lockFiles {
+		for {
+			locked, err := isLocked(lockFile)
+			if err != nil {
+				return fmt.Errorf("could not check lock file %s: %w", lockFile, err)
+			}
+			if !locked {
+				break
+			}
+			if time.Now().After(deadline) {
+				return fmt.Errorf("database lock %s is still held after %s", lockFile, timeout)
+			}
+			time.Sleep(pollInterval)
+		}
+	}
+	return nil
+}
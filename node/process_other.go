@@ -0,0 +1,29 @@
+//go:build !unix
+
+package node
+
+import (
+	"os"
+	"os/exec"
+)
+
+func setProcessGroup(_ *exec.Cmd) {}
+
+// killProcessGroup kills the process. Process groups are only supported on unix systems.
+func killProcessGroup(pid int) error {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return nil
+	}
+	return process.Kill()
+}
+
+func processAlive(pid int) bool {
+	_, err := os.FindProcess(pid)
+	return err == nil
+}
+
+// isLocked always reports unlocked files, since file locks are only checked on unix systems.
+func isLocked(_ string) (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,206 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNodeStarting is returned if the RPC of the node doesn't accept connections, e.g. because the node
+	// process is still starting.
+	ErrNodeStarting = errors.New("node RPC is not reachable")
+	// ErrNodeUnresponsive is returned if the RPC of the node accepts connections but doesn't respond in time,
+	// e.g. because the node is hung.
+	ErrNodeUnresponsive = errors.New("node RPC is not responding")
+	// ErrInvalidResponse is returned if the RPC of the node responds with an error or an unexpected body.
+	ErrInvalidResponse = errors.New("invalid node RPC response")
+)
+
+// Status is the status of the node. Only LatestHeight is known if the node doesn't expose /status.
+type Status struct {
+	LatestHeight    int64
+	EarliestHeight  int64
+	CatchingUp      bool
+	LatestBlockTime time.Time
+	Network         string
+	NodeId          string
+}
+
+type statusResponse struct {
+	Result struct {
+		NodeInfo struct {
+			Id      string `json:"id"`
+			Network string `json:"network"`
+		} `json:"node_info"`
+		SyncInfo struct {
+			LatestBlockHeight   string `json:"latest_block_height"`
+			LatestBlockTime     string `json:"latest_block_time"`
+			EarliestBlockHeight string `json:"earliest_block_height"`
+			CatchingUp          bool   `json:"catching_up"`
+		} `json:"sync_info"`
+	} `json:"result"`
+}
+
+type abciInfoResponse struct {
+	Result struct {
+		Response struct {
+			LastBlockHeight string `json:"last_block_height"`
+		} `json:"response"`
+	} `json:"result"`
+}
+
+// StatusClient queries the status of the node from its RPC.
+type StatusClient struct {
+	Endpoint string
+
+	httpClient *http.Client
+}
+
+func NewStatusClient(endpoint string) *StatusClient {
+	return &StatusClient{
+		Endpoint:   strings.TrimSuffix(endpoint, "/"),
+		httpClient: &http.Client{Timeout: rpcTimeout},
+	}
+}
+
+// Status returns the status of the node from /status. If /status responds with an invalid response, e.g.
+// because it isn't exposed, the latest height is queried from /abci_info instead. A node at height 0 is
+// returned without error, ErrNodeStarting and ErrNodeUnresponsive are returned if the node can't be reached.
+func (c *StatusClient) Status(ctx context.Context) (Status, error) {
+	status, err := c.status(ctx)
+	if err == nil || !errors.Is(err, ErrInvalidResponse) {
+		return status, err
+	}
+
+	height, abciErr := c.abciInfoHeight(ctx)
+	if abciErr != nil {
+		return Status{}, fmt.Errorf("%w, abci_info fallback failed: %s", err, abciErr)
+	}
+	return Status{LatestHeight: height}, nil
+}
+
+func (c *StatusClient) status(ctx context.Context) (Status, error) {
+	body, err := c.get(ctx, "status")
+	if err != nil {
+		return Status{}, err
+	}
+
+	var resp statusResponse
+	if err = json.Unmarshal(body, &resp); err != nil {
+		return Status{}, fmt.Errorf("%w: could not unmarshal status: %s", ErrInvalidResponse, err)
+	}
+
+	latest, err := parseHeight(resp.Result.SyncInfo.LatestBlockHeight)
+	if err != nil {
+		return Status{}, fmt.Errorf("%w: latest_block_height: %s", ErrInvalidResponse, err)
+	}
+	// The earliest height is missing in old Tendermint versions.
+	var earliest int64
+	if resp.Result.SyncInfo.EarliestBlockHeight != "" {
+		if earliest, err = parseHeight(resp.Result.SyncInfo.EarliestBlockHeight); err != nil {
+			return Status{}, fmt.Errorf("%w: earliest_block_height: %s", ErrInvalidResponse, err)
+		}
+	}
+
+	var blockTime time.Time
+	if resp.Result.SyncInfo.LatestBlockTime != "" {
+		if blockTime, err = time.Parse(time.RFC3339Nano, resp.Result.SyncInfo.LatestBlockTime); err != nil {
+			return Status{}, fmt.Errorf("%w: latest_block_time: %s", ErrInvalidResponse, err)
+		}
+	}
+
+	return Status{
+		LatestHeight:    latest,
+		EarliestHeight:  earliest,
+		CatchingUp:      resp.Result.SyncInfo.CatchingUp,
+		LatestBlockTime: blockTime,
+		Network:         resp.Result.NodeInfo.Network,
+		NodeId:          resp.Result.NodeInfo.Id,
+	}, nil
+}
+
+func (c *StatusClient) abciInfoHeight(ctx context.Context) (int64, error) {
+	body, err := c.get(ctx, "abci_info")
+	if err != nil {
+		return 0, err
+	}
+
+	var resp abciInfoResponse
+	if err = json.Unmarshal(body, &resp); err != nil {
+		return 0, fmt.Errorf("%w: could not unmarshal abci_info: %s", ErrInvalidResponse, err)
+	}
+
+	// The app omits the height before the first block was committed.
+	if resp.Result.Response.LastBlockHeight == "" {
+		return 0, nil
+	}
+	height, err := parseHeight(resp.Result.Response.LastBlockHeight)
+	if err != nil {
+		return 0, fmt.Errorf("%w: last_block_height: %s", ErrInvalidResponse, err)
+	}
+	return height, nil
+}
+
+// get calls a route of the RPC and returns the response body. Errors are typed as ErrNodeStarting,
+// ErrNodeUnresponsive or ErrInvalidResponse if they can be classified.
+func (c *StatusClient) get(ctx context.Context, route string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.Endpoint+"/"+route, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, classify(route, err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, classify(route, err)
+	}
+
+	var rpcErr rpcErrorResponse
+	if json.Unmarshal(body, &rpcErr) == nil && rpcErr.Error != nil {
+		return nil, fmt.Errorf("%w: %s: %s %s", ErrInvalidResponse, route, rpcErr.Error.Message, rpcErr.Error.Data)
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s responded with status %d", ErrInvalidResponse, route, response.StatusCode)
+	}
+
+	return body, nil
+}
+
+// classify types the error of a request which didn't return a response.
+func classify(route string, err error) error {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return fmt.Errorf("%w: %s: %s", ErrNodeStarting, route, err)
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return fmt.Errorf("%w: %s: %s", ErrNodeUnresponsive, route, err)
+	}
+
+	return fmt.Errorf("%s: %w", route, err)
+}
+
+func parseHeight(height string) (int64, error) {
+	h, err := strconv.ParseInt(height, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if h < 0 {
+		return 0, fmt.Errorf("negative height %d", h)
+	}
+	return h, nil
+}
@@ -0,0 +1,81 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// LeakReport is the result of a Ghost Mode verification.
+type LeakReport struct {
+	// Leaking is set if the node kept syncing or was connected to peers during the window.
+	Leaking     bool
+	StartHeight int
+	EndHeight   int
+	MaxPeers    int
+	Samples     int
+}
+
+// GhostVerifier samples the status and /net_info of a node in Ghost Mode over a time window to confirm
+// that its height stays flat and that it isn't connected to any peer.
+type GhostVerifier struct {
+	Status   *StatusClient
+	Peers    *PeerManager
+	Window   time.Duration
+	Interval time.Duration
+}
+
+func NewGhostVerifier(peers *PeerManager, window time.Duration) *GhostVerifier {
+	interval := window / 6
+	if interval < time.Second {
+		interval = time.Second
+	}
+	return &GhostVerifier{Status: NewStatusClient(peers.Endpoint), Peers: peers, Window: window, Interval: interval}
+}
+
+// Verify samples the node until the window has passed. Samples where the RPC isn't reachable yet are
+// skipped, an error is only returned if not a single sample could be taken.
+func (v *GhostVerifier) Verify(ctx context.Context) (LeakReport, error) {
+	var report LeakReport
+	var lastErr error
+
+	deadline := time.Now().Add(v.Window)
+	for {
+		status, err := v.Status.Status(ctx)
+		if err == nil {
+			height := int(status.LatestHeight)
+			var peers []Peer
+			peers, err = v.Peers.Peers(ctx)
+			if err == nil {
+				if report.Samples == 0 {
+					report.StartHeight = height
+				}
+				report.EndHeight = height
+				if len(peers) > report.MaxPeers {
+					report.MaxPeers = len(peers)
+				}
+				report.Samples++
+			}
+		}
+		if err != nil {
+			lastErr = err
+		}
+
+		if time.Now().Add(v.Interval).After(deadline) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return report, ctx.Err()
+		case <-time.After(v.Interval):
+		}
+	}
+
+	if report.Samples == 0 {
+		return report, fmt.Errorf("could not sample node: %w", lastErr)
+	}
+
+	report.Leaking = report.EndHeight > report.StartHeight || report.MaxPeers > 0
+	return report, nil
+}
@@ -0,0 +1,87 @@
+package notification
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EventType is the type of supervision event a notification is sent for.
+type EventType string
+
+const (
+	EventModeChange     EventType = "mode_change"
+	EventPruneStarted   EventType = "prune_started"
+	EventPruneFinished  EventType = "prune_finished"
+	EventPruneFailed    EventType = "prune_failed"
+	EventNodeCrash      EventType = "node_crash"
+	EventNodeBehindPool EventType = "node_behind_pool"
+	EventNodeStalled    EventType = "node_stalled"
+	EventPoolOutage     EventType = "pool_outage"
+	EventDiskThreshold  EventType = "disk_threshold"
+)
+
+// EventTypes contains all event types, which can be used to filter the events of a sink.
+var EventTypes = []EventType{
+	EventModeChange,
+	EventPruneStarted,
+	EventPruneFinished,
+	EventPruneFailed,
+	EventNodeCrash,
+	EventNodeBehindPool,
+	EventNodeStalled,
+	EventPoolOutage,
+	EventDiskThreshold,
+}
+
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Event is a supervision event. The pool ID and time are set by the notifier.
+type Event struct {
+	Type       EventType `json:"type"`
+	Severity   string    `json:"severity"`
+	Message    string    `json:"message"`
+	PoolId     int       `json:"pool_id"`
+	Mode       string    `json:"mode,omitempty"`
+	NodeHeight int       `json:"node_height,omitempty"`
+	PoolHeight int       `json:"pool_height,omitempty"`
+	Time       time.Time `json:"time"`
+	// Key distinguishes conditions of the same type for the de-duplication, e.g. an escalated stall. The
+	// message isn't used, since it can change while the condition lasts.
+	Key string `json:"-"`
+}
+
+// Text formats the event as a single message for chat sinks.
+func (e Event) Text() string {
+	var details []string
+	details = append(details, fmt.Sprintf("pool %d", e.PoolId))
+	if e.Mode != "" {
+		details = append(details, "mode "+e.Mode)
+	}
+	if e.NodeHeight > 0 {
+		details = append(details, fmt.Sprintf("node height %d", e.NodeHeight))
+	}
+	if e.PoolHeight > 0 {
+		details = append(details, fmt.Sprintf("pool height %d", e.PoolHeight))
+	}
+	return fmt.Sprintf("[supervysor] %s: %s (%s)", strings.ToUpper(e.Severity), e.Message, strings.Join(details, ", "))
+}
+
+// deduplicated reports whether repeated events of the type are de-duplicated. Only events reporting a
+// lasting condition are, every mode change, pruning and crash is sent.
+func (t EventType) deduplicated() bool {
+	switch t {
+	case EventNodeBehindPool, EventNodeStalled, EventPoolOutage, EventDiskThreshold:
+		return true
+	}
+	return false
+}
+
+// key identifies events which are considered duplicates.
+func (e Event) key() string {
+	return string(e.Type) + "\x00" + e.Key
+}
@@ -0,0 +1,158 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"cosmossdk.io/log"
+	"golang.org/x/exp/slices"
+
+	"github.com/KYVENetwork/supervysor/types"
+)
+
+const (
+	defaultDedupWindow = time.Hour
+	defaultRateLimit   = 30
+	sendTimeout        = 30 * time.Second
+)
+
+// Sink delivers notifications to an external service.
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, event Event) error
+}
+
+// sink is a configured sink with its event filter and rate limit state.
+type sink struct {
+	Sink
+	events []EventType
+	sent   []time.Time
+}
+
+// Notifier sends supervision events to all sinks subscribed to them. Identical events about a lasting
+// condition are only sent once within the de-duplication window and every sink sends at most RateLimit
+// notifications per hour.
+type Notifier struct {
+	PoolId      int
+	DedupWindow time.Duration
+	RateLimit   int
+
+	logger log.Logger
+	sinks  []*sink
+
+	mu   sync.Mutex
+	seen map[string]time.Time
+	wg   sync.WaitGroup
+}
+
+// NewNotifier creates a notifier with the sinks of the config. A notifier without sinks drops all events.
+func NewNotifier(logger log.Logger, cfg *types.SupervysorConfig) (*Notifier, error) {
+	n := &Notifier{
+		PoolId:      cfg.PoolId,
+		DedupWindow: defaultDedupWindow,
+		RateLimit:   defaultRateLimit,
+		logger:      logger,
+		seen:        make(map[string]time.Time),
+	}
+	if cfg.NotificationDedupWindow > 0 {
+		n.DedupWindow = time.Duration(cfg.NotificationDedupWindow) * time.Second
+	}
+	if cfg.NotificationRateLimit > 0 {
+		n.RateLimit = cfg.NotificationRateLimit
+	}
+
+	for i, notifierCfg := range cfg.Notifiers {
+		s, err := NewSink(notifierCfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid notifier %d: %w", i, err)
+		}
+
+		var events []EventType
+		for _, event := range notifierCfg.Events {
+			if !slices.Contains(EventTypes, EventType(event)) {
+				return nil, fmt.Errorf("invalid notifier %d: unknown event %s", i, event)
+			}
+			events = append(events, EventType(event))
+		}
+
+		n.sinks = append(n.sinks, &sink{Sink: s, events: events})
+	}
+
+	return n, nil
+}
+
+// Notify sends the event asynchronously, so a slow or unreachable sink never blocks the supervision.
+func (n *Notifier) Notify(event Event) {
+	if n == nil || len(n.sinks) == 0 {
+		return
+	}
+
+	event.PoolId = n.PoolId
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for key, last := range n.seen {
+		if event.Time.Sub(last) >= n.DedupWindow {
+			delete(n.seen, key)
+		}
+	}
+	if event.Type.deduplicated() {
+		if _, ok := n.seen[event.key()]; ok {
+			n.logger.Debug("dropping duplicate notification", "event", event.Type)
+			return
+		}
+		n.seen[event.key()] = event.Time
+	}
+
+	for _, s := range n.sinks {
+		if len(s.events) > 0 && !slices.Contains(s.events, event.Type) {
+			continue
+		}
+		if !s.allow(event.Time, n.RateLimit) {
+			n.logger.Error("notification rate limit reached, dropping notification", "sink", s.Name(), "event", event.Type)
+			continue
+		}
+
+		n.wg.Add(1)
+		go func(s *sink) {
+			defer n.wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+			defer cancel()
+
+			if err := s.Send(ctx, event); err != nil {
+				n.logger.Error("could not send notification", "sink", s.Name(), "event", event.Type, "err", err)
+			}
+		}(s)
+	}
+}
+
+// Wait waits until all pending notifications were sent, e.g. before the supervysor exits.
+func (n *Notifier) Wait() {
+	if n != nil {
+		n.wg.Wait()
+	}
+}
+
+// allow reports whether the sink may send another notification within the last hour and records it.
+func (s *sink) allow(now time.Time, limit int) bool {
+	var recent []time.Time
+	for _, t := range s.sent {
+		if now.Sub(t) < time.Hour {
+			recent = append(recent, t)
+		}
+	}
+	s.sent = recent
+
+	if len(s.sent) >= limit {
+		return false
+	}
+	s.sent = append(s.sent, now)
+	return true
+}
@@ -0,0 +1,107 @@
+package notification
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"cosmossdk.io/log"
+)
+
+// recorder is a sink which records the sent events.
+type recorder struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (r *recorder) Name() string { return "recorder" }
+
+func (r *recorder) Send(_ context.Context, event Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, event)
+	return nil
+}
+
+func newTestNotifier(rateLimit int, events ...EventType) (*Notifier, *recorder) {
+	r := &recorder{}
+	return &Notifier{
+		PoolId:      2,
+		DedupWindow: time.Hour,
+		RateLimit:   rateLimit,
+		logger:      log.NewNopLogger(),
+		sinks:       []*sink{{Sink: r, events: events}},
+		seen:        make(map[string]time.Time),
+	}, r
+}
+
+func TestNotifierDeduplicatesLastingConditions(t *testing.T) {
+	n, r := newTestNotifier(100)
+	start := time.Now()
+
+	// The message of a stalled node changes with the stall duration, the events are still duplicates.
+	n.Notify(Event{Type: EventNodeStalled, Message: "node height did not advance for 15m0s", Time: start})
+	n.Notify(Event{Type: EventNodeStalled, Message: "node height did not advance for 30m0s", Time: start.Add(15 * time.Minute)})
+	// An escalation of the same condition isn't a duplicate.
+	n.Notify(Event{Type: EventNodeStalled, Message: "node height did not advance for 45m0s, shutting down", Key: "shutdown", Time: start.Add(30 * time.Minute)})
+	// The condition is reported again after the de-duplication window.
+	n.Notify(Event{Type: EventNodeStalled, Message: "node height did not advance for 1h15m0s", Time: start.Add(75 * time.Minute)})
+	// Transitions are never de-duplicated.
+	n.Notify(Event{Type: EventModeChange, Message: "switched from normal to ghost mode", Time: start})
+	n.Notify(Event{Type: EventModeChange, Message: "switched from normal to ghost mode", Time: start.Add(time.Minute)})
+	n.Wait()
+
+	want := []string{
+		"node height did not advance for 15m0s",
+		"node height did not advance for 45m0s, shutting down",
+		"node height did not advance for 1h15m0s",
+		"switched from normal to ghost mode",
+		"switched from normal to ghost mode",
+	}
+	if len(r.events) != len(want) {
+		t.Fatalf("sent %d events, want %d: %v", len(r.events), len(want), r.events)
+	}
+	sent := make(map[string]int)
+	for _, event := range r.events {
+		sent[event.Message]++
+		if event.PoolId != 2 {
+			t.Errorf("pool id = %d, want 2", event.PoolId)
+		}
+	}
+	for _, message := range want {
+		if sent[message] == 0 {
+			t.Errorf("%q was not sent", message)
+		}
+		sent[message]--
+	}
+}
+
+func TestNotifierRateLimit(t *testing.T) {
+	n, r := newTestNotifier(2)
+	start := time.Now()
+
+	for i := 0; i < 3; i++ {
+		n.Notify(Event{Type: EventModeChange, Time: start.Add(time.Duration(i) * time.Minute)})
+	}
+	// The first notification is older than an hour, so another one can be sent.
+	n.Notify(Event{Type: EventModeChange, Time: start.Add(61 * time.Minute)})
+	n.Wait()
+
+	if len(r.events) != 3 {
+		t.Errorf("sent %d events, want 3", len(r.events))
+	}
+}
+
+func TestNotifierFiltersEvents(t *testing.T) {
+	n, r := newTestNotifier(100, EventNodeCrash)
+
+	n.Notify(Event{Type: EventModeChange})
+	n.Notify(Event{Type: EventNodeCrash})
+	n.Wait()
+
+	if len(r.events) != 1 || r.events[0].Type != EventNodeCrash {
+		t.Errorf("sent %v, want only %s", r.events, EventNodeCrash)
+	}
+}
@@ -0,0 +1,230 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/KYVENetwork/supervysor/types"
+)
+
+const (
+	SinkWebhook  = "webhook"
+	SinkSlack    = "slack"
+	SinkDiscord  = "discord"
+	SinkTelegram = "telegram"
+	SinkEmail    = "email"
+
+	defaultTelegramAPI = "https://api.telegram.org"
+)
+
+// NewSink creates the sink of the configured type.
+func NewSink(cfg types.NotifierType) (Sink, error) {
+	switch cfg.Type {
+	case SinkWebhook:
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("webhook requires a URL")
+		}
+		var tmpl *template.Template
+		if cfg.Template != "" {
+			var err error
+			tmpl, err = template.New("webhook").Funcs(template.FuncMap{"json": jsonString}).Parse(cfg.Template)
+			if err != nil {
+				return nil, fmt.Errorf("invalid webhook template: %w", err)
+			}
+		}
+		return &WebhookSink{URL: cfg.URL, Template: tmpl, Headers: cfg.Headers}, nil
+	case SinkSlack:
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("slack requires a webhook URL")
+		}
+		return &SlackSink{URL: cfg.URL}, nil
+	case SinkDiscord:
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("discord requires a webhook URL")
+		}
+		return &DiscordSink{URL: cfg.URL}, nil
+	case SinkTelegram:
+		if cfg.Token == "" || cfg.ChatId == "" {
+			return nil, fmt.Errorf("telegram requires a bot token and chat ID")
+		}
+		api := cfg.URL
+		if api == "" {
+			api = defaultTelegramAPI
+		}
+		return &TelegramSink{API: strings.TrimSuffix(api, "/"), Token: cfg.Token, ChatId: cfg.ChatId}, nil
+	case SinkEmail:
+		if cfg.SMTPHost == "" || cfg.From == "" || len(cfg.To) == 0 {
+			return nil, fmt.Errorf("email requires an SMTP host, sender and recipients")
+		}
+		port := cfg.SMTPPort
+		if port == 0 {
+			port = 587
+		}
+		return &EmailSink{Host: cfg.SMTPHost, Port: port, Username: cfg.Username, Password: cfg.Password, From: cfg.From, To: cfg.To}, nil
+	default:
+		return nil, fmt.Errorf("unknown notifier type %s", cfg.Type)
+	}
+}
+
+// WebhookSink posts the event as JSON or rendered with a template to a URL.
+type WebhookSink struct {
+	URL      string
+	Template *template.Template
+	Headers  map[string]string
+}
+
+func (s *WebhookSink) Name() string { return SinkWebhook }
+
+func (s *WebhookSink) Send(ctx context.Context, event Event) error {
+	var body []byte
+	if s.Template != nil {
+		var buf bytes.Buffer
+		if err := s.Template.Execute(&buf, event); err != nil {
+			return fmt.Errorf("could not render webhook template: %w", err)
+		}
+		body = buf.Bytes()
+	} else {
+		var err error
+		if body, err = json.Marshal(event); err != nil {
+			return err
+		}
+	}
+	return postJSON(ctx, s.URL, body, s.Headers)
+}
+
+// SlackSink posts the event to a Slack incoming webhook.
+type SlackSink struct {
+	URL string
+}
+
+func (s *SlackSink) Name() string { return SinkSlack }
+
+func (s *SlackSink) Send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(map[string]string{"text": event.Text()})
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, s.URL, body, nil)
+}
+
+// DiscordSink posts the event to a Discord webhook.
+type DiscordSink struct {
+	URL string
+}
+
+func (s *DiscordSink) Name() string { return SinkDiscord }
+
+func (s *DiscordSink) Send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(map[string]string{"content": event.Text()})
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, s.URL, body, nil)
+}
+
+// TelegramSink sends the event as message of a Telegram bot.
+type TelegramSink struct {
+	API    string
+	Token  string
+	ChatId string
+}
+
+func (s *TelegramSink) Name() string { return SinkTelegram }
+
+func (s *TelegramSink) Send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(map[string]string{"chat_id": s.ChatId, "text": event.Text()})
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, fmt.Sprintf("%s/bot%s/sendMessage", s.API, s.Token), body, nil)
+}
+
+// EmailSink sends the event as email through an SMTP server.
+type EmailSink struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+func (s *EmailSink) Name() string { return SinkEmail }
+
+func (s *EmailSink) Send(ctx context.Context, event Event) error {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", s.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(s.To, ", "))
+	fmt.Fprintf(&msg, "Subject: [supervysor] %s: %s\r\n", event.Severity, event.Type)
+	fmt.Fprintf(&msg, "Date: %s\r\n", event.Time.Format("Mon, 02 Jan 2006 15:04:05 -0700"))
+	fmt.Fprintf(&msg, "Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	fmt.Fprintf(&msg, "%s\r\n", event.Text())
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+
+	// smtp.SendMail doesn't support contexts, which is why it's run until the context is done.
+	errs := make(chan error, 1)
+	go func() {
+		errs <- smtp.SendMail(net.JoinHostPort(s.Host, strconv.Itoa(s.Port)), auth, s.From, s.To, msg.Bytes())
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-errs:
+		return err
+	}
+}
+
+func postJSON(ctx context.Context, endpoint string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return redact(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return redact(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// redact removes the URL from request errors, since webhook URLs and the Telegram API URL contain secrets
+// which must not be logged.
+func redact(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return fmt.Errorf("%s request failed: %w", urlErr.Op, urlErr.Err)
+	}
+	return err
+}
+
+// jsonString escapes a value for webhook templates, e.g. {"text": {{ json .Message }}}.
+func jsonString(v any) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
@@ -0,0 +1,192 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KYVENetwork/supervysor/types"
+)
+
+// request is a request received by the sink stand-in.
+type request struct {
+	Path    string
+	Headers http.Header
+	Body    map[string]any
+}
+
+// newStandIn starts a server which records the JSON body of every request and responds with the status.
+func newStandIn(t *testing.T, status int) (*httptest.Server, <-chan request) {
+	t.Helper()
+
+	requests := make(chan request, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("could not read body: %s", err)
+		}
+		var body map[string]any
+		if err = json.Unmarshal(raw, &body); err != nil {
+			t.Errorf("body is not JSON: %s", raw)
+		}
+		requests <- request{Path: r.URL.Path, Headers: r.Header, Body: body}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, requests
+}
+
+func testEvent() Event {
+	return Event{
+		Type:       EventModeChange,
+		Severity:   SeverityInfo,
+		Message:    "switched from normal to ghost mode",
+		PoolId:     2,
+		Mode:       "ghost",
+		NodeHeight: 1200,
+		PoolHeight: 1000,
+		Time:       time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func send(t *testing.T, cfg types.NotifierType) error {
+	t.Helper()
+
+	s, err := NewSink(cfg)
+	if err != nil {
+		t.Fatalf("could not create sink: %s", err)
+	}
+	return s.Send(context.Background(), testEvent())
+}
+
+func TestSinkPayloads(t *testing.T) {
+	text := "[supervysor] INFO: switched from normal to ghost mode (pool 2, mode ghost, node height 1200, pool height 1000)"
+
+	tests := []struct {
+		name     string
+		cfg      types.NotifierType
+		wantPath string
+		want     map[string]any
+	}{
+		{
+			name:     "webhook",
+			cfg:      types.NotifierType{Type: SinkWebhook, URL: "/hook"},
+			wantPath: "/hook",
+			want: map[string]any{
+				"type":        "mode_change",
+				"severity":    "info",
+				"message":     "switched from normal to ghost mode",
+				"pool_id":     float64(2),
+				"mode":        "ghost",
+				"node_height": float64(1200),
+				"pool_height": float64(1000),
+				"time":        "2024-01-02T03:04:05Z",
+			},
+		},
+		{
+			name:     "webhook with template",
+			cfg:      types.NotifierType{Type: SinkWebhook, URL: "/hook", Template: `{"summary": {{ json .Message }}, "pool": {{ .PoolId }}}`},
+			wantPath: "/hook",
+			want:     map[string]any{"summary": "switched from normal to ghost mode", "pool": float64(2)},
+		},
+		{
+			name:     "slack",
+			cfg:      types.NotifierType{Type: SinkSlack, URL: "/services/T000/B000/secret"},
+			wantPath: "/services/T000/B000/secret",
+			want:     map[string]any{"text": text},
+		},
+		{
+			name:     "discord",
+			cfg:      types.NotifierType{Type: SinkDiscord, URL: "/api/webhooks/1/secret"},
+			wantPath: "/api/webhooks/1/secret",
+			want:     map[string]any{"content": text},
+		},
+		{
+			name:     "telegram",
+			cfg:      types.NotifierType{Type: SinkTelegram, Token: "123:secret", ChatId: "-100"},
+			wantPath: "/bot123:secret/sendMessage",
+			want:     map[string]any{"chat_id": "-100", "text": text},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newStandIn(t, http.StatusOK)
+
+			if tt.cfg.Type == SinkTelegram {
+				tt.cfg.URL = server.URL
+			} else {
+				tt.cfg.URL = server.URL + tt.cfg.URL
+			}
+			if err := send(t, tt.cfg); err != nil {
+				t.Fatalf("could not send: %s", err)
+			}
+
+			got := <-requests
+			if got.Path != tt.wantPath {
+				t.Errorf("path = %s, want %s", got.Path, tt.wantPath)
+			}
+			if got.Headers.Get("Content-Type") != "application/json" {
+				t.Errorf("content type = %s, want application/json", got.Headers.Get("Content-Type"))
+			}
+			gotBody, _ := json.Marshal(got.Body)
+			wantBody, _ := json.Marshal(tt.want)
+			if string(gotBody) != string(wantBody) {
+				t.Errorf("body = %s, want %s", gotBody, wantBody)
+			}
+		})
+	}
+}
+
+func TestWebhookHeaders(t *testing.T) {
+	server, requests := newStandIn(t, http.StatusOK)
+
+	cfg := types.NotifierType{Type: SinkWebhook, URL: server.URL, Headers: map[string]string{"Authorization": "Bearer token"}}
+	if err := send(t, cfg); err != nil {
+		t.Fatalf("could not send: %s", err)
+	}
+
+	if got := (<-requests).Headers.Get("Authorization"); got != "Bearer token" {
+		t.Errorf("authorization header = %q, want %q", got, "Bearer token")
+	}
+}
+
+func TestSinkErrorStatus(t *testing.T) {
+	server, _ := newStandIn(t, http.StatusForbidden)
+
+	err := send(t, types.NotifierType{Type: SinkSlack, URL: server.URL + "/services/secret"})
+	if err == nil || !strings.Contains(err.Error(), "unexpected status code 403") {
+		t.Errorf("err = %v, want unexpected status code 403", err)
+	}
+}
+
+func TestSinkErrorsRedactURL(t *testing.T) {
+	server, _ := newStandIn(t, http.StatusOK)
+	// Requests to the closed server fail with a connection error, which contains the URL by default.
+	server.Close()
+
+	tests := []types.NotifierType{
+		{Type: SinkWebhook, URL: server.URL + "/hook?token=secret"},
+		{Type: SinkSlack, URL: server.URL + "/services/T000/B000/secret"},
+		{Type: SinkDiscord, URL: server.URL + "/api/webhooks/1/secret"},
+		{Type: SinkTelegram, URL: server.URL, Token: "123:secret", ChatId: "-100"},
+	}
+
+	for _, cfg := range tests {
+		t.Run(cfg.Type, func(t *testing.T) {
+			err := send(t, cfg)
+			if err == nil {
+				t.Fatal("expected error for closed server")
+			}
+			if strings.Contains(err.Error(), "secret") || strings.Contains(err.Error(), server.URL) {
+				t.Errorf("error contains the URL: %s", err)
+			}
+		})
+	}
+}
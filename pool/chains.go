@@ -0,0 +1,47 @@
+package pool
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/KYVENetwork/supervysor/types"
+)
+
+// GetEndpoints resolves the KYVE REST endpoints of the given chain ID. User-defined chains from the config
+// take precedence over the built-in chains, so endpoints of official chains can be overwritten as well.
+// Fallback endpoints are always appended. An unknown chain ID is only accepted if at least one fallback
+// endpoint is provided, which allows running supervysor against new chains without a code change.
+func GetEndpoints(chainId string, chains []types.ChainType, fallbackEndpoints string) ([]string, error) {
+	var endpoints []string
+
+	known := false
+	for _, chain := range chains {
+		if chain.ChainId == chainId {
+			endpoints = append(endpoints, chain.Endpoints...)
+			known = true
+		}
+	}
+
+	if !known {
+		endpoints, known = types.BuiltinChains[chainId]
+	}
+
+	var result []string
+	for _, endpoint := range append(append([]string{}, endpoints...), strings.Split(fallbackEndpoints, ",")...) {
+		endpoint = strings.TrimSuffix(strings.TrimSpace(endpoint), "/")
+		if endpoint != "" && !slices.Contains(result, endpoint) {
+			result = append(result, endpoint)
+		}
+	}
+
+	if !known && len(result) == 0 {
+		return nil, fmt.Errorf("unknown chainId %s: please define its endpoints in the config or provide fallback endpoints", chainId)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no endpoints defined for chainId %s", chainId)
+	}
+
+	return result, nil
+}
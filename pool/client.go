@@ -0,0 +1,200 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"time"
+
+	"cosmossdk.io/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/KYVENetwork/supervysor/types"
+)
+
+const (
+	defaultRequestTimeout = 10
+	defaultRequestRetries = 3
+)
+
+// StatusError is returned if a KYVE endpoint responds with an unexpected HTTP status code.
+type StatusError struct {
+	Endpoint   string
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s responded with status %d: %s", e.Endpoint, e.StatusCode, e.Body)
+}
+
+// retryable reports whether a request which failed with this status code should be repeated.
+func (e *StatusError) retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+// Client queries the KYVE API for pool information. Every endpoint is requested with a timeout
+// and retried with an exponential backoff before the next endpoint is used. If all endpoints fail,
+// the errors of all endpoints are returned combined.
+type Client struct {
+	Endpoints []string
+	PoolId    int
+	Timeout   time.Duration
+	Retries   int
+
+	// HeightMode defines how the pool height is determined, see GetPoolHeights.
+	HeightMode string
+	// HeightTolerance is the maximum number of blocks an endpoint may deviate from the median height.
+	HeightTolerance int
+	// Quorum is the minimum number of endpoints which need to respond in median or minimum mode.
+	Quorum int
+
+	// Metrics is optional and used to expose per-endpoint heights and latencies.
+	Metrics *types.Metrics
+
+	// Transports maps the URL scheme of an endpoint to the transport used to query it.
+	Transports map[string]Transport
+
+	logger log.Logger
+}
+
+// NewClient creates a pool client for the given endpoints, which are usually resolved with GetEndpoints.
+// The request and quorum settings are taken from the config, unset values fall back to the defaults.
+func NewClient(logger log.Logger, endpoints []string, cfg *types.SupervysorConfig) *Client {
+	timeout := cfg.PoolRequestTimeout
+	if timeout <= 0 {
+		timeout = defaultRequestTimeout
+	}
+	retries := cfg.PoolRequestRetries
+	if retries <= 0 {
+		retries = defaultRequestRetries
+	}
+	heightMode := cfg.PoolHeightMode
+	if heightMode == "" {
+		heightMode = HeightModeFirst
+	}
+	quorum := cfg.PoolHeightQuorum
+	if quorum <= 0 {
+		quorum = 1
+	}
+
+	return &Client{
+		Endpoints:       endpoints,
+		PoolId:          cfg.PoolId,
+		Timeout:         time.Duration(timeout) * time.Second,
+		Retries:         retries,
+		HeightMode:      heightMode,
+		HeightTolerance: cfg.PoolHeightTolerance,
+		Quorum:          quorum,
+		Transports:      DefaultTransports(),
+		logger:          logger,
+	}
+}
+
+// Close releases the connections held by the transports of the client.
+func (c *Client) Close() error {
+	var errs []error
+	for _, transport := range c.Transports {
+		if err := transport.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GetPool requests the pool from the configured endpoints in order and returns the first valid response.
+func (c *Client) GetPool(ctx context.Context) (*types.SettingsResponse, error) {
+	if len(c.Endpoints) == 0 {
+		return nil, fmt.Errorf("no KYVE endpoints configured")
+	}
+
+	var errs []error
+	for _, endpoint := range c.Endpoints {
+		resp, err := c.requestPoolWithBackoff(ctx, endpoint)
+		if err == nil {
+			return resp, nil
+		}
+		errs = append(errs, err)
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return nil, fmt.Errorf("all KYVE endpoints failed: %w", errors.Join(errs...))
+}
+
+// requestPoolWithBackoff requests the pool from a single endpoint and retries with an exponential backoff
+// as long as the error is temporary.
+func (c *Client) requestPoolWithBackoff(ctx context.Context, endpoint string) (*types.SettingsResponse, error) {
+	var err error
+	for i := 0; i < c.Retries; i++ {
+		if i > 0 {
+			delay := time.Duration(math.Pow(2, float64(i-1))*500) * time.Millisecond
+			c.logger.Debug("retrying KYVE endpoint", "endpoint", endpoint, "delay", delay.String(), "err", err)
+
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+
+		start := time.Now()
+
+		var resp *types.SettingsResponse
+		resp, err = c.requestPool(ctx, endpoint)
+		if c.Metrics != nil {
+			c.Metrics.PoolEndpointLatency.WithLabelValues(endpoint).Set(time.Since(start).Seconds())
+		}
+		if err == nil {
+			return resp, nil
+		}
+		if c.Metrics != nil && ctx.Err() == nil {
+			c.Metrics.PoolEndpointErrors.WithLabelValues(endpoint).Inc()
+		}
+
+		if !retryable(err) {
+			break
+		}
+	}
+	return nil, err
+}
+
+// requestPool makes a single request for the pool to the given endpoint, using the transport
+// which matches the URL scheme of the endpoint.
+func (c *Client) requestPool(ctx context.Context, endpoint string) (*types.SettingsResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid KYVE endpoint %s: %w", endpoint, err)
+	}
+
+	transport, ok := c.Transports[u.Scheme]
+	if !ok {
+		return nil, fmt.Errorf("unsupported scheme %s of KYVE endpoint %s", u.Scheme, endpoint)
+	}
+
+	return transport.QueryPool(ctx, endpoint, c.PoolId)
+}
+
+// retryable reports whether a failed request should be repeated. Client errors like a pool that
+// doesn't exist are not retried, everything else (e.g. timeouts or unavailable endpoints) is.
+func retryable(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.retryable()
+	}
+
+	switch status.Code(err) {
+	case codes.NotFound, codes.InvalidArgument, codes.Unimplemented, codes.PermissionDenied, codes.Unauthenticated:
+		return false
+	}
+	return true
+}
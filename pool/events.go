@@ -0,0 +1,153 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"cosmossdk.io/log"
+	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+const (
+	// EventBundleFinalized is the typed event the KYVE chain emits once a bundle of a pool got finalized.
+	EventBundleFinalized = "kyve.bundles.v1beta1.EventBundleFinalized"
+
+	eventsSubscriber     = "supervysor"
+	eventsHealthInterval = 30 * time.Second
+	eventsMaxBackoff     = 5 * time.Minute
+)
+
+// EventSubscriber subscribes to the Tendermint RPC websocket of the KYVE chain and notifies about finalized
+// bundles of the configured pool, so the pool height can be updated immediately instead of waiting for the
+// next poll. If the subscription drops, the supervysor falls back to polling until it is re-established.
+type EventSubscriber struct {
+	Endpoint string
+	PoolId   int
+
+	updates   chan struct{}
+	connected atomic.Bool
+	logger    log.Logger
+}
+
+// NewEventSubscriber creates a subscriber for the given Tendermint RPC endpoint of the KYVE chain.
+func NewEventSubscriber(logger log.Logger, endpoint string, poolId int) *EventSubscriber {
+	return &EventSubscriber{
+		Endpoint: strings.TrimSuffix(endpoint, "/"),
+		PoolId:   poolId,
+		updates:  make(chan struct{}, 1),
+		logger:   logger,
+	}
+}
+
+// Updates returns a channel which receives a value every time a bundle of the pool was finalized.
+// Notifications are coalesced, so a slow reader never blocks the subscription.
+func (s *EventSubscriber) Updates() <-chan struct{} {
+	return s.updates
+}
+
+// Connected reports whether the subscription is currently active.
+func (s *EventSubscriber) Connected() bool {
+	return s.connected.Load()
+}
+
+// Run keeps the subscription alive until the context is cancelled, reconnecting with an exponential backoff.
+func (s *EventSubscriber) Run(ctx context.Context) {
+	backoff := time.Second
+	for {
+		started := time.Now()
+		err := s.subscribe(ctx)
+		s.connected.Store(false)
+
+		if ctx.Err() != nil {
+			return
+		}
+		s.logger.Error("pool event subscription dropped, falling back to polling", "endpoint", s.Endpoint, "err", err)
+
+		// Reset the backoff if the subscription was alive for a while.
+		if time.Since(started) > eventsMaxBackoff {
+			backoff = time.Second
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > eventsMaxBackoff {
+			backoff = eventsMaxBackoff
+		}
+	}
+}
+
+// subscribe establishes a single subscription and blocks until it drops or the context is cancelled.
+func (s *EventSubscriber) subscribe(ctx context.Context) error {
+	client, err := rpchttp.New(s.Endpoint, "/websocket")
+	if err != nil {
+		return fmt.Errorf("could not create RPC client: %w", err)
+	}
+	if err = client.Start(); err != nil {
+		return fmt.Errorf("could not start RPC client: %w", err)
+	}
+	defer func() {
+		_ = client.UnsubscribeAll(context.Background(), eventsSubscriber)
+		_ = client.Stop()
+	}()
+
+	query := fmt.Sprintf("tm.event = 'Tx' AND %s.pool_id EXISTS", EventBundleFinalized)
+	events, err := client.Subscribe(ctx, eventsSubscriber, query, 100)
+	if err != nil {
+		return fmt.Errorf("could not subscribe to %s: %w", query, err)
+	}
+
+	s.connected.Store(true)
+	s.logger.Info("subscribed to pool events", "endpoint", s.Endpoint)
+
+	health := time.NewTicker(eventsHealthInterval)
+	defer health.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-events:
+			if !ok {
+				return fmt.Errorf("event channel closed")
+			}
+			if s.matchesPool(event) {
+				s.logger.Debug("bundle finalized")
+				select {
+				case s.updates <- struct{}{}:
+				default:
+				}
+			}
+		case <-health.C:
+			healthCtx, cancel := context.WithTimeout(ctx, eventsHealthInterval)
+			_, err = client.Health(healthCtx)
+			cancel()
+			if err != nil {
+				return fmt.Errorf("health check failed: %w", err)
+			}
+		}
+	}
+}
+
+// matchesPool checks whether a finalized bundle event belongs to the configured pool. Attribute values of
+// typed events are JSON encoded, so they are unquoted before comparing.
+func (s *EventSubscriber) matchesPool(event ctypes.ResultEvent) bool {
+	for _, poolId := range event.Events[EventBundleFinalized+".pool_id"] {
+		if unquoted, err := strconv.Unquote(poolId); err == nil {
+			poolId = unquoted
+		}
+		if poolId == strconv.Itoa(s.PoolId) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,243 @@
+package pool
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/KYVENetwork/supervysor/types"
+)
+
+// QueryPoolMethod is the full gRPC method name of the pool query of the kyve.query.v1beta1.QueryPool service.
+const QueryPoolMethod = "/kyve.query.v1beta1.QueryPool/Pool"
+
+// Field numbers of the KYVE protobuf messages which are required to decode pool responses. Decoding them
+// by hand avoids depending on the whole KYVE chain module for a single query.
+const (
+	// kyve.query.v1beta1.QueryPoolRequest
+	fieldRequestId = 1
+	// kyve.query.v1beta1.QueryPoolResponse
+	fieldResponsePool = 1
+	// kyve.query.v1beta1.PoolResponse
+	fieldPoolResponseData   = 2
+	fieldPoolResponseStatus = 7
+	// kyve.pool.v1beta1.Pool
+	fieldPoolStartKey       = 6
+	fieldPoolCurrentKey     = 7
+	fieldPoolUploadInterval = 11
+	fieldPoolMaxBundleSize  = 14
+	fieldPoolDisabled       = 15
+)
+
+// GRPCTransport queries pools with the kyve.query.v1beta1.QueryPool gRPC service. Connections are
+// established lazily and reused for every endpoint.
+type GRPCTransport struct {
+	// DialOptions are appended to the default dial options, e.g. to dial an in-process server.
+	DialOptions []grpc.DialOption
+
+	mu    sync.Mutex
+	conns map[string]*grpc.ClientConn
+}
+
+func NewGRPCTransport() *GRPCTransport {
+	return &GRPCTransport{conns: make(map[string]*grpc.ClientConn)}
+}
+
+// QueryPool requests the pool from the given grpc:// or grpcs:// endpoint.
+func (t *GRPCTransport) QueryPool(ctx context.Context, endpoint string, poolId int) (*types.SettingsResponse, error) {
+	conn, err := t.getConn(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	req := protowire.AppendTag(nil, fieldRequestId, protowire.VarintType)
+	req = protowire.AppendVarint(req, uint64(poolId))
+
+	var res []byte
+	if err = conn.Invoke(ctx, QueryPoolMethod, &req, &res, grpc.ForceCodec(rawCodec{})); err != nil {
+		return nil, fmt.Errorf("failed requesting KYVE endpoint %s: %w", endpoint, err)
+	}
+
+	resp, err := decodeQueryPoolResponse(res)
+	if err != nil {
+		return nil, fmt.Errorf("failed decoding KYVE endpoint response of %s: %w", endpoint, err)
+	}
+	return resp, nil
+}
+
+// Close closes all open connections.
+func (t *GRPCTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var errs []error
+	for endpoint, conn := range t.conns {
+		if err := conn.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		delete(t.conns, endpoint)
+	}
+	return errors.Join(errs...)
+}
+
+func (t *GRPCTransport) getConn(endpoint string) (*grpc.ClientConn, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if conn, ok := t.conns[endpoint]; ok {
+		return conn, nil
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gRPC endpoint %s: %w", endpoint, err)
+	}
+
+	var opts []grpc.DialOption
+	if u.Scheme == "grpcs" {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})))
+	} else {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+	opts = append(opts, t.DialOptions...)
+
+	conn, err := grpc.Dial(u.Host, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("could not dial gRPC endpoint %s: %w", endpoint, err)
+	}
+	t.conns[endpoint] = conn
+
+	return conn, nil
+}
+
+// decodeQueryPoolResponse decodes a kyve.query.v1beta1.QueryPoolResponse into the same structure
+// which is used for REST responses.
+func decodeQueryPoolResponse(b []byte) (*types.SettingsResponse, error) {
+	var resp types.SettingsResponse
+
+	poolResponse, err := findMessage(b, fieldResponsePool)
+	if err != nil {
+		return nil, err
+	}
+	if poolResponse == nil {
+		return nil, fmt.Errorf("response does not contain a pool")
+	}
+
+	var data []byte
+	err = walkFields(poolResponse, func(num protowire.Number, typ protowire.Type, value []byte, varint uint64) {
+		switch {
+		case num == fieldPoolResponseData && typ == protowire.BytesType:
+			data = value
+		case num == fieldPoolResponseStatus && typ == protowire.VarintType:
+			// Unknown statuses keep their number, so they are handled like unknown REST statuses.
+			if varint < uint64(len(types.PoolStatuses)) {
+				resp.Pool.Status = types.PoolStatuses[varint]
+			} else {
+				resp.Pool.Status = fmt.Sprintf("POOL_STATUS_%d", varint)
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = walkFields(data, func(num protowire.Number, typ protowire.Type, value []byte, varint uint64) {
+		switch {
+		case num == fieldPoolStartKey && typ == protowire.BytesType:
+			resp.Pool.Data.StartKey = string(value)
+		case num == fieldPoolCurrentKey && typ == protowire.BytesType:
+			resp.Pool.Data.CurrentKey = string(value)
+		case num == fieldPoolUploadInterval && typ == protowire.VarintType:
+			resp.Pool.Data.UploadInterval = strconv.FormatUint(varint, 10)
+		case num == fieldPoolMaxBundleSize && typ == protowire.VarintType:
+			resp.Pool.Data.MaxBundleSize = strconv.FormatUint(varint, 10)
+		case num == fieldPoolDisabled && typ == protowire.VarintType:
+			resp.Pool.Data.Disabled = varint != 0
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// findMessage returns the bytes of the last embedded message with the given field number.
+func findMessage(b []byte, field protowire.Number) ([]byte, error) {
+	var msg []byte
+	err := walkFields(b, func(num protowire.Number, typ protowire.Type, value []byte, _ uint64) {
+		if num == field && typ == protowire.BytesType {
+			msg = value
+		}
+	})
+	return msg, err
+}
+
+// walkFields calls fn for every top-level field of an encoded protobuf message. Length-delimited
+// fields are passed as value, varint fields as varint.
+func walkFields(b []byte, fn func(num protowire.Number, typ protowire.Type, value []byte, varint uint64)) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		switch typ {
+		case protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			fn(num, typ, nil, v)
+			b = b[n:]
+		case protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			fn(num, typ, v, 0)
+			b = b[n:]
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			b = b[n:]
+		}
+	}
+	return nil
+}
+
+// rawCodec passes already encoded protobuf messages through to the gRPC connection.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return nil, fmt.Errorf("unexpected message type %T", v)
+	}
+	return *b, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("unexpected message type %T", v)
+	}
+	*b = append((*b)[:0], data...)
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
@@ -0,0 +1,283 @@
+package pool
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"golang.org/x/exp/slices"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/KYVENetwork/supervysor/types"
+)
+
+// kyveFiles describes the KYVE messages of kyve/pool/v1beta1/pool.proto and kyve/query/v1beta1/pools.proto
+// by field name, so the test server encodes its responses independently of the field numbers in grpc.go.
+func kyveFiles(t *testing.T) *protoregistry.Files {
+	t.Helper()
+
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(number),
+			Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:   typ.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+	repeated := func(f *descriptorpb.FieldDescriptorProto) *descriptorpb.FieldDescriptorProto {
+		f.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+		return f
+	}
+	const (
+		u64   = descriptorpb.FieldDescriptorProto_TYPE_UINT64
+		str   = descriptorpb.FieldDescriptorProto_TYPE_STRING
+		boolT = descriptorpb.FieldDescriptorProto_TYPE_BOOL
+		msg   = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+		enum  = descriptorpb.FieldDescriptorProto_TYPE_ENUM
+	)
+
+	var statusValues []*descriptorpb.EnumValueDescriptorProto
+	for i, status := range types.PoolStatuses {
+		statusValues = append(statusValues, &descriptorpb.EnumValueDescriptorProto{Name: proto.String(status), Number: proto.Int32(int32(i))})
+	}
+
+	poolFile := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("kyve/pool/v1beta1/pool.proto"),
+		Package: proto.String("kyve.pool.v1beta1"),
+		Syntax:  proto.String("proto3"),
+		EnumType: []*descriptorpb.EnumDescriptorProto{
+			{Name: proto.String("PoolStatus"), Value: statusValues},
+		},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Pool"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("id", 1, u64, ""),
+				field("name", 2, str, ""),
+				field("runtime", 3, str, ""),
+				field("logo", 4, str, ""),
+				field("config", 5, str, ""),
+				field("start_key", 6, str, ""),
+				field("current_key", 7, str, ""),
+				field("current_summary", 8, str, ""),
+				field("current_index", 9, u64, ""),
+				field("total_bundles", 10, u64, ""),
+				field("upload_interval", 11, u64, ""),
+				field("inflation_share_weight", 12, u64, ""),
+				field("min_delegation", 13, u64, ""),
+				field("max_bundle_size", 14, u64, ""),
+				field("disabled", 15, boolT, ""),
+			},
+		}},
+	}
+
+	queryFile := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("kyve/query/v1beta1/pools.proto"),
+		Package:    proto.String("kyve.query.v1beta1"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"kyve/pool/v1beta1/pool.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name:  proto.String("QueryPoolRequest"),
+				Field: []*descriptorpb.FieldDescriptorProto{field("id", 1, u64, "")},
+			},
+			{
+				Name: proto.String("PoolResponse"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("id", 1, u64, ""),
+					field("data", 2, msg, ".kyve.pool.v1beta1.Pool"),
+					repeated(field("stakers", 4, str, "")),
+					field("total_self_delegation", 5, u64, ""),
+					field("total_delegation", 6, u64, ""),
+					field("status", 7, enum, ".kyve.pool.v1beta1.PoolStatus"),
+					field("account", 8, str, ""),
+					field("account_balance", 9, u64, ""),
+				},
+			},
+			{
+				Name:  proto.String("QueryPoolResponse"),
+				Field: []*descriptorpb.FieldDescriptorProto{field("pool", 1, msg, ".kyve.query.v1beta1.PoolResponse")},
+			},
+		},
+	}
+
+	files, err := protodesc.NewFiles(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{poolFile, queryFile}})
+	if err != nil {
+		t.Fatalf("could not build KYVE descriptors: %s", err)
+	}
+	return files
+}
+
+func messageDescriptor(t *testing.T, files *protoregistry.Files, name string) protoreflect.MessageDescriptor {
+	t.Helper()
+
+	d, err := files.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		t.Fatalf("could not find %s: %s", name, err)
+	}
+	return d.(protoreflect.MessageDescriptor)
+}
+
+// startQueryPoolServer serves the kyve.query.v1beta1.QueryPool/Pool method in-process and responds with
+// the response returned by respond for the requested pool ID.
+func startQueryPoolServer(t *testing.T, files *protoregistry.Files, respond func(id uint64) proto.Message) *bufconn.Listener {
+	t.Helper()
+
+	requestDesc := messageDescriptor(t, files, "kyve.query.v1beta1.QueryPoolRequest")
+	listener := bufconn.Listen(1 << 20)
+
+	server := grpc.NewServer()
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "kyve.query.v1beta1.QueryPool",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Pool",
+			Handler: func(_ interface{}, _ context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+				req := dynamicpb.NewMessage(requestDesc)
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				return respond(req.Get(requestDesc.Fields().ByName("id")).Uint()), nil
+			},
+		}},
+	}, struct{}{})
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	return listener
+}
+
+// newQueryPoolResponse encodes a QueryPoolResponse with all fields of the pool set, including the ones which
+// aren't decoded by the transport.
+func newQueryPoolResponse(t *testing.T, files *protoregistry.Files, id uint64, status protoreflect.EnumNumber) proto.Message {
+	t.Helper()
+
+	poolDesc := messageDescriptor(t, files, "kyve.pool.v1beta1.Pool")
+	pool := dynamicpb.NewMessage(poolDesc)
+	set := func(m *dynamicpb.Message, name string, v protoreflect.Value) {
+		m.Set(m.Descriptor().Fields().ByName(protoreflect.Name(name)), v)
+	}
+	set(pool, "id", protoreflect.ValueOfUint64(id))
+	set(pool, "name", protoreflect.ValueOfString("Cosmos Hub"))
+	set(pool, "runtime", protoreflect.ValueOfString("@kyvejs/tendermint-bsync"))
+	set(pool, "config", protoreflect.ValueOfString(`{"network":"cosmoshub-4"}`))
+	set(pool, "start_key", protoreflect.ValueOfString("5200791"))
+	set(pool, "current_key", protoreflect.ValueOfString("5300790"))
+	set(pool, "current_summary", protoreflect.ValueOfString("5300790"))
+	set(pool, "current_index", protoreflect.ValueOfUint64(100000))
+	set(pool, "total_bundles", protoreflect.ValueOfUint64(100))
+	set(pool, "upload_interval", protoreflect.ValueOfUint64(60))
+	set(pool, "inflation_share_weight", protoreflect.ValueOfUint64(1000000))
+	set(pool, "min_delegation", protoreflect.ValueOfUint64(100000000000))
+	set(pool, "max_bundle_size", protoreflect.ValueOfUint64(1000))
+	set(pool, "disabled", protoreflect.ValueOfBool(true))
+
+	poolResponseDesc := messageDescriptor(t, files, "kyve.query.v1beta1.PoolResponse")
+	poolResponse := dynamicpb.NewMessage(poolResponseDesc)
+	set(poolResponse, "id", protoreflect.ValueOfUint64(id))
+	set(poolResponse, "data", protoreflect.ValueOfMessage(pool))
+	stakers := poolResponse.NewField(poolResponseDesc.Fields().ByName("stakers")).List()
+	stakers.Append(protoreflect.ValueOfString("kyve1staker"))
+	set(poolResponse, "stakers", protoreflect.ValueOfList(stakers))
+	set(poolResponse, "total_self_delegation", protoreflect.ValueOfUint64(42))
+	set(poolResponse, "total_delegation", protoreflect.ValueOfUint64(4242))
+	set(poolResponse, "status", protoreflect.ValueOfEnum(status))
+	set(poolResponse, "account", protoreflect.ValueOfString("kyve1pool"))
+	set(poolResponse, "account_balance", protoreflect.ValueOfUint64(7))
+
+	resp := dynamicpb.NewMessage(messageDescriptor(t, files, "kyve.query.v1beta1.QueryPoolResponse"))
+	set(resp, "pool", protoreflect.ValueOfMessage(poolResponse))
+	return resp
+}
+
+func newBufconnTransport(listener *bufconn.Listener) *GRPCTransport {
+	transport := NewGRPCTransport()
+	transport.DialOptions = []grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+	}
+	return transport
+}
+
+func TestGRPCTransportQueryPool(t *testing.T) {
+	files := kyveFiles(t)
+
+	var requestedId uint64
+	listener := startQueryPoolServer(t, files, func(id uint64) proto.Message {
+		requestedId = id
+		return newQueryPoolResponse(t, files, id, 3)
+	})
+
+	transport := newBufconnTransport(listener)
+	defer transport.Close()
+
+	resp, err := transport.QueryPool(context.Background(), "grpc://bufnet", 12)
+	if err != nil {
+		t.Fatalf("could not query pool: %s", err)
+	}
+
+	if requestedId != 12 {
+		t.Errorf("requested pool %d, want 12", requestedId)
+	}
+	if resp.Pool.Status != types.PoolStatusNoFunds {
+		t.Errorf("status = %s, want %s", resp.Pool.Status, types.PoolStatusNoFunds)
+	}
+
+	data := resp.Pool.Data
+	if data.StartKey != "5200791" || data.CurrentKey != "5300790" {
+		t.Errorf("keys = %s, %s, want 5200791, 5300790", data.StartKey, data.CurrentKey)
+	}
+	if data.UploadInterval != "60" || data.MaxBundleSize != "1000" {
+		t.Errorf("upload interval = %s, max bundle size = %s, want 60, 1000", data.UploadInterval, data.MaxBundleSize)
+	}
+	if !data.Disabled {
+		t.Errorf("disabled = false, want true")
+	}
+}
+
+func TestGRPCTransportQueryPoolUnknownStatus(t *testing.T) {
+	files := kyveFiles(t)
+	listener := startQueryPoolServer(t, files, func(id uint64) proto.Message {
+		return newQueryPoolResponse(t, files, id, 42)
+	})
+
+	transport := newBufconnTransport(listener)
+	defer transport.Close()
+
+	resp, err := transport.QueryPool(context.Background(), "grpc://bufnet", 1)
+	if err != nil {
+		t.Fatalf("could not query pool: %s", err)
+	}
+	// Unknown statuses must not be mapped to a known status, since unknown statuses are held like REST ones.
+	if slices.Contains(types.PoolStatuses, resp.Pool.Status) {
+		t.Errorf("unknown status mapped to %s", resp.Pool.Status)
+	}
+}
+
+func TestGRPCTransportQueryPoolWithoutPool(t *testing.T) {
+	files := kyveFiles(t)
+	listener := startQueryPoolServer(t, files, func(uint64) proto.Message {
+		return dynamicpb.NewMessage(messageDescriptor(t, files, "kyve.query.v1beta1.QueryPoolResponse"))
+	})
+
+	transport := newBufconnTransport(listener)
+	defer transport.Close()
+
+	if _, err := transport.QueryPool(context.Background(), "grpc://bufnet", 1); err == nil {
+		t.Fatalf("expected error for response without pool")
+	}
+}
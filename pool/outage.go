@@ -0,0 +1,102 @@
+package pool
+
+import (
+	"math"
+	"time"
+)
+
+const maxOutageBackoff = 60 * time.Second
+
+// OutageState describes how the supervysor should react to a failed pool height request.
+type OutageState int
+
+const (
+	// OutageDegraded means the KYVE API is unreachable, but the grace period has not passed yet.
+	OutageDegraded OutageState = iota
+	// OutageAlert is returned once when the outage exceeds the grace period.
+	OutageAlert
+	// OutageOngoing means the outage exceeds the grace period and was already alerted.
+	OutageOngoing
+	// OutageEscalate means the outage exceeds the maximum duration and the node should be stopped.
+	OutageEscalate
+)
+
+// OutageTracker tracks consecutive failures of the KYVE API. During an outage the node is kept running in
+// its current mode, an alert is raised after the grace period and the outage is only escalated if it lasts
+// longer than the maximum duration. A maximum duration of zero disables the escalation.
+type OutageTracker struct {
+	GracePeriod time.Duration
+	MaxDuration time.Duration
+
+	since    time.Time
+	failures int
+	alerted  bool
+}
+
+// NewOutageTracker creates an outage tracker with the grace period and maximum duration in seconds.
+func NewOutageTracker(gracePeriod int, maxDuration int) *OutageTracker {
+	return &OutageTracker{
+		GracePeriod: time.Duration(gracePeriod) * time.Second,
+		MaxDuration: time.Duration(maxDuration) * time.Second,
+	}
+}
+
+// Failure registers a failed request and returns the resulting outage state.
+func (o *OutageTracker) Failure(now time.Time) OutageState {
+	if o.failures == 0 {
+		o.since = now
+	}
+	o.failures++
+
+	duration := now.Sub(o.since)
+
+	if o.MaxDuration > 0 && duration >= o.MaxDuration {
+		return OutageEscalate
+	}
+	if duration >= o.GracePeriod {
+		if !o.alerted {
+			o.alerted = true
+			return OutageAlert
+		}
+		return OutageOngoing
+	}
+	return OutageDegraded
+}
+
+// Success resets the tracker and returns the duration of the outage which just ended, if there was one.
+func (o *OutageTracker) Success(now time.Time) (time.Duration, bool) {
+	if o.failures == 0 {
+		return 0, false
+	}
+	duration := now.Sub(o.since)
+
+	o.failures = 0
+	o.alerted = false
+
+	return duration, true
+}
+
+// Duration returns the duration of the current outage.
+func (o *OutageTracker) Duration(now time.Time) time.Duration {
+	if o.failures == 0 {
+		return 0
+	}
+	return now.Sub(o.since)
+}
+
+// Backoff returns the delay until the next request, which doubles with every consecutive failure
+// starting from the regular interval.
+func (o *OutageTracker) Backoff(interval time.Duration) time.Duration {
+	if o.failures == 0 {
+		return interval
+	}
+	exponent := math.Min(float64(o.failures-1), 10)
+	delay := time.Duration(math.Pow(2, exponent)) * interval
+	if delay > maxOutageBackoff {
+		delay = maxOutageBackoff
+	}
+	if delay < interval {
+		delay = interval
+	}
+	return delay
+}
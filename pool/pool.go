@@ -1,77 +1,76 @@
 package pool
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
-	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/KYVENetwork/supervysor/types"
 )
 
-// GetPoolHeight retrieves the KYVE pool height by using a list of endpoints (& optionally fallback endpoints)
-// based on the provided chain and pool ID.
-func GetPoolHeight(chainId string, poolId int, fallbackEndpoints string) (int, error) {
-	var endpoints []string
-	var err error
-
-	if chainId == "korellia" {
-		endpoints = types.KorelliaEndpoints
-	} else if chainId == "kaon-1" {
-		endpoints = types.KaonEndpoints
-	} else if chainId == "kyve-1" {
-		endpoints = types.MainnetEndpoints
-	} else {
-		return 0, fmt.Errorf("unknown chainId")
+// GetPoolHeight retrieves the KYVE pool height from the configured endpoints.
+func (c *Client) GetPoolHeight(ctx context.Context) (int, error) {
+	resp, err := c.GetPool(ctx)
+	if err != nil {
+		return 0, err
 	}
 
-	for _, endpoint := range append(endpoints, strings.Split(fallbackEndpoints, ",")...) {
-		if endpoint != "" {
-			if height, err := requestPoolHeight(poolId, endpoint); err == nil {
-				return height, err
-			}
-		}
-	}
-	return 0, err
+	return ParsePoolHeight(resp)
 }
 
-// requestPoolHeight retrieves KYVE pool height by making an GET request to the given endpoint.
-func requestPoolHeight(poolId int, endpoint string) (int, error) {
-	poolEndpoint := endpoint + "/kyve/query/v1beta1/pool/" + strconv.FormatInt(int64(poolId), 10)
-
-	response, err := http.Get(poolEndpoint)
-	if err != nil {
-		return 0, fmt.Errorf("failed requesting KYVE endpoint: %s", err)
-	}
-
-	responseData, err := io.ReadAll(response.Body)
+// GetPoolSettings retrieves the KYVE pool settings required to calculate the pruning settings.
+func (c *Client) GetPoolSettings(ctx context.Context) (types.PoolSettingsType, error) {
+	resp, err := c.GetPool(ctx)
 	if err != nil {
-		return 0, fmt.Errorf("failed reading KYVE endpoint response: %s", err)
+		return types.PoolSettingsType{}, err
 	}
 
-	var resp types.SettingsResponse
-	err = json.Unmarshal(responseData, &resp)
-	if err != nil {
-		return 0, fmt.Errorf("failed unmarshalling KYVE endpoint response: %s", err)
-	}
+	return ParsePoolSettings(resp)
+}
 
-	var poolHeight int
+// ParsePoolHeight extracts the pool height from a pool response. The current_key is used
+// if the pool already has finalized bundles, otherwise the start_key.
+func ParsePoolHeight(resp *types.SettingsResponse) (int, error) {
 	currentKey := resp.Pool.Data.CurrentKey
 
 	if currentKey == "" {
-		startKey := resp.Pool.Data.StartKey
-		poolHeight, err = strconv.Atoi(startKey)
+		poolHeight, err := strconv.Atoi(resp.Pool.Data.StartKey)
 		if err != nil {
 			return 0, fmt.Errorf("could not convert poolHeight from start_key to int: %s", err)
 		}
-	} else {
-		poolHeight, err = strconv.Atoi(currentKey)
-		if err != nil {
-			return 0, fmt.Errorf("could not convert poolHeight from current_key to int: %s", err)
-		}
+		return poolHeight, nil
+	}
+
+	poolHeight, err := strconv.Atoi(currentKey)
+	if err != nil {
+		return 0, fmt.Errorf("could not convert poolHeight from current_key to int: %s", err)
+	}
+	return poolHeight, nil
+}
+
+// ParsePoolStatus extracts the pool status from a pool response. Older APIs which don't return a status
+// are treated as active, unless the pool is disabled.
+func ParsePoolStatus(resp *types.SettingsResponse) string {
+	if resp.Pool.Status != "" {
+		return resp.Pool.Status
+	}
+	if resp.Pool.Data.Disabled {
+		return types.PoolStatusDisabled
+	}
+	return types.PoolStatusActive
+}
+
+// ParsePoolSettings extracts the upload interval and max bundle size from a pool response.
+func ParsePoolSettings(resp *types.SettingsResponse) (types.PoolSettingsType, error) {
+	interval, err := strconv.Atoi(resp.Pool.Data.UploadInterval)
+	if err != nil {
+		return types.PoolSettingsType{}, fmt.Errorf("could not convert upload_interval to int: %s", err)
+	}
+
+	size, err := strconv.Atoi(resp.Pool.Data.MaxBundleSize)
+	if err != nil {
+		return types.PoolSettingsType{}, fmt.Errorf("could not convert max_bundle_size to int: %s", err)
 	}
 
-	return poolHeight, err
+	return types.PoolSettingsType{MaxBundleSize: size, UploadInterval: interval}, nil
 }
@@ -0,0 +1,156 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/KYVENetwork/supervysor/types"
+)
+
+const (
+	// HeightModeFirst uses the height of the first endpoint which responds.
+	HeightModeFirst = "first"
+	// HeightModeMedian queries all endpoints in parallel and uses the median height for mode switching
+	// and the minimum height for pruning.
+	HeightModeMedian = "median"
+	// HeightModeMinimum queries all endpoints in parallel and uses the minimum height for everything.
+	HeightModeMinimum = "minimum"
+)
+
+// PoolHeights contains the pool height which should be used for mode switching and the (lower or equal)
+// pool height which is safe to use for pruning, together with the pool status, the pool settings and
+// the heights reported by every endpoint.
+type PoolHeights struct {
+	Height      int
+	PruneHeight int
+	Status      string
+	Settings    types.PoolSettingsType
+	Endpoints   map[string]int
+	Outliers    []string
+}
+
+// GetPoolHeights retrieves the KYVE pool height according to the configured height mode. In "first" mode
+// the endpoints are requested in order, in "median" and "minimum" mode all endpoints are requested in
+// parallel and endpoints disagreeing with the median by more than the tolerance are reported as outliers.
+func (c *Client) GetPoolHeights(ctx context.Context) (PoolHeights, error) {
+	switch c.HeightMode {
+	case HeightModeFirst:
+		resp, err := c.GetPool(ctx)
+		if err != nil {
+			return PoolHeights{}, err
+		}
+		height, err := ParsePoolHeight(resp)
+		if err != nil {
+			return PoolHeights{}, err
+		}
+		// The settings are informational only, so a response without them is still valid.
+		settings, _ := ParsePoolSettings(resp)
+
+		return PoolHeights{Height: height, PruneHeight: height, Status: ParsePoolStatus(resp), Settings: settings}, nil
+	case HeightModeMedian, HeightModeMinimum:
+		return c.getPoolHeightsQuorum(ctx)
+	default:
+		return PoolHeights{}, fmt.Errorf("unknown pool height mode %s", c.HeightMode)
+	}
+}
+
+// getPoolHeightsQuorum requests all endpoints in parallel and aggregates their heights.
+func (c *Client) getPoolHeightsQuorum(ctx context.Context) (PoolHeights, error) {
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs []error
+	)
+	heights := make(map[string]int)
+	statuses := make(map[string]int)
+	var settings types.PoolSettingsType
+
+	for _, endpoint := range c.Endpoints {
+		wg.Add(1)
+		go func(endpoint string) {
+			defer wg.Done()
+
+			resp, err := c.requestPoolWithBackoff(ctx, endpoint)
+			var height int
+			if err == nil {
+				height, err = ParsePoolHeight(resp)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", endpoint, err))
+				return
+			}
+			heights[endpoint] = height
+			statuses[ParsePoolStatus(resp)]++
+			if s, err := ParsePoolSettings(resp); err == nil {
+				settings = s
+			}
+		}(endpoint)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		c.logger.Error("some KYVE endpoints failed", "failed", len(errs), "total", len(c.Endpoints), "err", errors.Join(errs...))
+	}
+	if len(heights) == 0 || len(heights) < c.Quorum {
+		return PoolHeights{}, fmt.Errorf("only %d of %d KYVE endpoints responded, quorum is %d: %w", len(heights), len(c.Endpoints), c.Quorum, errors.Join(errs...))
+	}
+
+	values := make([]int, 0, len(heights))
+	for _, height := range heights {
+		values = append(values, height)
+	}
+	sort.Ints(values)
+
+	// For an even number of heights the lower median is used, as it is the safer choice.
+	median := values[(len(values)-1)/2]
+	minimum := values[0]
+
+	result := PoolHeights{Height: median, PruneHeight: minimum, Status: majorityStatus(statuses), Settings: settings, Endpoints: heights}
+	if c.HeightMode == HeightModeMinimum {
+		result.Height = minimum
+	}
+
+	for endpoint, height := range heights {
+		diff := height - median
+		if diff < 0 {
+			diff = -diff
+		}
+		outlier := diff > c.HeightTolerance
+		if outlier {
+			result.Outliers = append(result.Outliers, endpoint)
+			c.logger.Info("KYVE endpoint disagrees with median pool height", "endpoint", endpoint, "height", height, "median", median, "tolerance", c.HeightTolerance)
+		}
+
+		if c.Metrics != nil {
+			c.Metrics.PoolEndpointHeight.WithLabelValues(endpoint).Set(float64(height))
+			if outlier {
+				c.Metrics.PoolEndpointOutlier.WithLabelValues(endpoint).Set(1)
+			} else {
+				c.Metrics.PoolEndpointOutlier.WithLabelValues(endpoint).Set(0)
+			}
+		}
+	}
+	sort.Strings(result.Outliers)
+
+	return result, nil
+}
+
+// majorityStatus returns the pool status reported by most endpoints. Ties are resolved in favour of
+// a status other than active, since reacting to a halted pool is the safer choice.
+func majorityStatus(statuses map[string]int) string {
+	var result string
+	for status, count := range statuses {
+		if count > statuses[result] ||
+			(count == statuses[result] && result == types.PoolStatusActive) ||
+			(count == statuses[result] && status != types.PoolStatusActive && status < result) {
+			result = status
+		}
+	}
+	return result
+}
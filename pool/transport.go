@@ -0,0 +1,85 @@
+package pool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/KYVENetwork/supervysor/types"
+)
+
+const maxErrorBodyLength = 256
+
+// Transport queries a single KYVE endpoint for a pool. The transport of an endpoint is selected by the
+// URL scheme of the endpoint, e.g. https://api.kyve.network or grpc://127.0.0.1:9090.
+type Transport interface {
+	QueryPool(ctx context.Context, endpoint string, poolId int) (*types.SettingsResponse, error)
+	Close() error
+}
+
+// DefaultTransports returns the REST transport for http(s) endpoints and the gRPC transport for
+// grpc (plaintext) and grpcs (TLS) endpoints.
+func DefaultTransports() map[string]Transport {
+	rest := NewRESTTransport()
+	grpcTransport := NewGRPCTransport()
+
+	return map[string]Transport{
+		"http":  rest,
+		"https": rest,
+		"grpc":  grpcTransport,
+		"grpcs": grpcTransport,
+	}
+}
+
+// RESTTransport queries pools with the REST path /kyve/query/v1beta1/pool/{id}.
+type RESTTransport struct {
+	httpClient *http.Client
+}
+
+func NewRESTTransport() *RESTTransport {
+	return &RESTTransport{httpClient: &http.Client{}}
+}
+
+// QueryPool makes a single GET request for the pool to the given endpoint.
+func (t *RESTTransport) QueryPool(ctx context.Context, endpoint string, poolId int) (*types.SettingsResponse, error) {
+	poolEndpoint := endpoint + "/kyve/query/v1beta1/pool/" + strconv.FormatInt(int64(poolId), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, poolEndpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating request for %s: %w", endpoint, err)
+	}
+
+	response, err := t.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed requesting KYVE endpoint %s: %w", endpoint, err)
+	}
+	defer response.Body.Close()
+
+	responseData, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading KYVE endpoint response of %s: %w", endpoint, err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		body := string(responseData)
+		if len(body) > maxErrorBodyLength {
+			body = body[:maxErrorBodyLength]
+		}
+		return nil, &StatusError{Endpoint: endpoint, StatusCode: response.StatusCode, Body: body}
+	}
+
+	var resp types.SettingsResponse
+	if err = json.Unmarshal(responseData, &resp); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling KYVE endpoint response of %s: %w", endpoint, err)
+	}
+
+	return &resp, nil
+}
+
+func (t *RESTTransport) Close() error {
+	t.httpClient.CloseIdleConnections()
+	return nil
+}
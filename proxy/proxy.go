@@ -0,0 +1,291 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"cosmossdk.io/log"
+
+	"github.com/KYVENetwork/supervysor/executor"
+	"github.com/KYVENetwork/supervysor/types"
+)
+
+const (
+	defaultTimeout = 60 * time.Second
+	retryInterval  = 500 * time.Millisecond
+	// retryAfter is the number of seconds clients are asked to wait if the node is unavailable.
+	retryAfter = 10
+	// maxBodySize limits the request bodies which are buffered to be retried.
+	maxBodySize = 10 << 20
+)
+
+var (
+	errRestarting = errors.New("node is restarting")
+	errNotRunning = errors.New("node is not running")
+)
+
+// Proxy forwards requests to an endpoint of the node, e.g. its RPC or REST API. Requests which arrive while
+// the node is restarted by the supervysor are held until it's available again and requests which can't
+// reach the node are retried, both up to the timeout. Afterwards, they are answered with 503 and a
+// Retry-After header.
+type Proxy struct {
+	Name    string
+	Listen  string
+	Target  *url.URL
+	Timeout time.Duration
+	// Metrics records the requests per endpoint, no metrics are recorded if it is nil.
+	Metrics *types.Metrics
+
+	health func() executor.Health
+	logger log.Logger
+	proxy  *httputil.ReverseProxy
+}
+
+// NewProxies creates the proxies of the config. The health of the node is used to detect planned restarts.
+func NewProxies(logger log.Logger, cfg *types.SupervysorConfig, health func() executor.Health) ([]*Proxy, error) {
+	var proxies []*Proxy
+	for i, c := range cfg.Proxies {
+		p, err := NewProxy(logger, c, health)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy %d: %s", i, err)
+		}
+		proxies = append(proxies, p)
+	}
+	return proxies, nil
+}
+
+// NewProxy creates a proxy which listens on the configured address and forwards all requests to the target.
+func NewProxy(logger log.Logger, cfg types.ProxyType, health func() executor.Health) (*Proxy, error) {
+	if strings.TrimSpace(cfg.Name) == "" {
+		return nil, fmt.Errorf("name is not defined")
+	}
+	if strings.TrimSpace(cfg.Listen) == "" {
+		return nil, fmt.Errorf("listen address is not defined")
+	}
+	target, err := url.Parse(cfg.Target)
+	if err != nil || target.Scheme == "" || target.Host == "" {
+		return nil, fmt.Errorf("invalid target %q", cfg.Target)
+	}
+
+	p := &Proxy{
+		Name:    cfg.Name,
+		Listen:  cfg.Listen,
+		Target:  target,
+		Timeout: defaultTimeout,
+		health:  health,
+		logger:  logger.With("proxy", cfg.Name),
+	}
+	if cfg.Timeout > 0 {
+		p.Timeout = time.Duration(cfg.Timeout) * time.Second
+	}
+
+	p.proxy = httputil.NewSingleHostReverseProxy(target)
+	p.proxy.Transport = &transport{proxy: p, base: http.DefaultTransport}
+	p.proxy.ErrorHandler = p.unavailable
+
+	return p, nil
+}
+
+// ListenAndServe serves the proxy until the context is cancelled.
+func (p *Proxy) ListenAndServe(ctx context.Context) error {
+	server := &http.Server{Addr: p.Listen, Handler: p}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	p.logger.Info("proxy listening", "listen", p.Listen, "target", p.Target.String())
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+	// Buffer the body, so the request can be sent again if the node couldn't be reached.
+	if r.Body != nil && r.Body != http.NoBody {
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+		if err != nil {
+			http.Error(rec, "could not read request body", http.StatusBadRequest)
+			p.observe(r, rec.status, start)
+			return
+		}
+		if len(body) > maxBodySize {
+			http.Error(rec, "request body too large", http.StatusRequestEntityTooLarge)
+			p.observe(r, rec.status, start)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	p.proxy.ServeHTTP(rec, r)
+	p.observe(r, rec.status, start)
+}
+
+func (p *Proxy) observe(r *http.Request, status int, start time.Time) {
+	if p.Metrics == nil {
+		return
+	}
+	endpoint := endpointLabel(r.URL.Path)
+	p.Metrics.ProxyRequests.WithLabelValues(p.Name, endpoint, strconv.Itoa(status)).Inc()
+	p.Metrics.ProxyRequestDuration.WithLabelValues(p.Name, endpoint).Observe(time.Since(start).Seconds())
+}
+
+// unavailable answers requests which couldn't be forwarded to the node.
+func (p *Proxy) unavailable(w http.ResponseWriter, r *http.Request, err error) {
+	if r.Context().Err() != nil {
+		// The client is gone, nobody receives the response anyway.
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+
+	p.logger.Info("node unavailable, rejecting request", "endpoint", endpointLabel(r.URL.Path), "err", err)
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	http.Error(w, fmt.Sprintf("node unavailable: %s", err), http.StatusServiceUnavailable)
+}
+
+// transport holds requests while the node is restarted and retries requests which couldn't connect to it.
+type transport struct {
+	proxy *Proxy
+	base  http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	deadline := time.Now().Add(t.proxy.Timeout)
+
+	for {
+		if err := t.waitForNode(req.Context(), deadline); err != nil {
+			return nil, err
+		}
+
+		resp, err := t.base.RoundTrip(req)
+		if err == nil || !isDialError(err) || !time.Now().Add(retryInterval).Before(deadline) {
+			return resp, err
+		}
+
+		if t.proxy.Metrics != nil {
+			t.proxy.Metrics.ProxyRetries.WithLabelValues(t.proxy.Name).Inc()
+		}
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(retryInterval):
+		}
+
+		// The body may have been consumed by the failed attempt.
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// waitForNode waits until the node isn't restarted anymore. It fails immediately if the node isn't running
+// without being restarted, e.g. because it crashed.
+func (t *transport) waitForNode(ctx context.Context, deadline time.Time) error {
+	for {
+		health := t.proxy.health()
+		if !health.Restarting {
+			if !health.Running {
+				return errNotRunning
+			}
+			return nil
+		}
+		if !time.Now().Add(retryInterval).Before(deadline) {
+			return errRestarting
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// isDialError checks whether the request failed before it was sent, because the node didn't accept the
+// connection yet.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// rpcRoutes are the routes of the Tendermint RPC, which are recorded with their name.
+var rpcRoutes = map[string]bool{
+	"abci_info": true, "abci_query": true, "block": true, "block_by_hash": true, "block_results": true,
+	"block_search": true, "blockchain": true, "broadcast_evidence": true, "broadcast_tx_async": true,
+	"broadcast_tx_commit": true, "broadcast_tx_sync": true, "check_tx": true, "commit": true,
+	"consensus_params": true, "consensus_state": true, "dump_consensus_state": true, "genesis": true,
+	"genesis_chunked": true, "health": true, "net_info": true, "num_unconfirmed_txs": true, "status": true,
+	"subscribe": true, "tx": true, "tx_search": true, "unconfirmed_txs": true, "unsubscribe": true,
+	"unsubscribe_all": true, "validators": true, "websocket": true,
+}
+
+// restRoutes are the namespaces of the Cosmos SDK REST API, which are recorded with their first two segments.
+var restRoutes = map[string]bool{
+	"cosmos/auth": true, "cosmos/authz": true, "cosmos/bank": true, "cosmos/base": true,
+	"cosmos/consensus": true, "cosmos/distribution": true, "cosmos/evidence": true, "cosmos/feegrant": true,
+	"cosmos/gov": true, "cosmos/group": true, "cosmos/mint": true, "cosmos/nft": true, "cosmos/params": true,
+	"cosmos/slashing": true, "cosmos/staking": true, "cosmos/tx": true, "cosmos/upgrade": true,
+	"cosmwasm/wasm": true, "ibc/apps": true, "ibc/core": true, "ibc/lightclients": true,
+}
+
+// endpointLabel maps the path to a known RPC route or REST namespace to keep the number of metric labels
+// bounded, e.g. /cosmos/bank/v1beta1/balances/... is recorded as /cosmos/bank. Unknown paths are recorded
+// as other, since they can be chosen freely by clients.
+func endpointLabel(path string) string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		// JSON-RPC requests are posted to the root path.
+		return "/"
+	}
+
+	segments := strings.SplitN(path, "/", 3)
+	if len(segments) == 1 && rpcRoutes[segments[0]] {
+		return "/" + segments[0]
+	}
+	if len(segments) >= 2 && restRoutes[segments[0]+"/"+segments[1]] {
+		return "/" + segments[0] + "/" + segments[1]
+	}
+	return "other"
+}
+
+// statusRecorder records the status code of the response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap allows the reverse proxy to flush and hijack the underlying connection, e.g. for websockets.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -2,24 +2,15 @@ package helpers
 
 import (
 	"bufio"
-	"encoding/json"
 	"fmt"
-	"io"
 	"math"
-	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
-
-	"github.com/KYVENetwork/supervysor/types"
-
-	"cosmossdk.io/log"
 )
 
-var logger = log.NewLogger(os.Stdout)
-
 func CheckBinaryPath(path string) error {
 	_, err := exec.LookPath(path)
 	if err != nil {
@@ -54,33 +45,6 @@ func CalculateMaxDifference(maxBundleSize int, uploadInterval int) int {
 			float64(maxBundleSize) / float64(uploadInterval) * 60 * 60 * 24 * 2))
 }
 
-// GetPoolSettings retrieves KYVE pool settings by using a list of endpoints (& optionally fallback endpoints)
-// based on the provided chain and pool ID.
-func GetPoolSettings(poolId int, chainId string, fallbackEndpoints string) ([2]int, error) {
-	var endpoints []string
-	var err error
-
-	if chainId == "korellia" {
-		endpoints = types.KorelliaEndpoints
-	} else if chainId == "kaon-1" {
-		endpoints = types.KaonEndpoints
-	} else if chainId == "kyve-1" {
-		endpoints = types.MainnetEndpoints
-	} else {
-		return [2]int{}, fmt.Errorf("unknown chainId")
-	}
-
-	for _, endpoint := range append(endpoints, strings.Split(fallbackEndpoints, ",")...) {
-		if endpoint != "" {
-			if height, err := requestPoolSettings(poolId, endpoint); err == nil {
-				return height, err
-			}
-		}
-	}
-
-	return [2]int{}, err
-}
-
 // SetPruningSettings updates the pruning settings in the app.toml file of the given home directory.
 // It reads the current file, modifies the relevant lines and writes the updated lines back to the file.
 func SetPruningSettings(homePath string, stateRequests bool, keepRecent int, interval int) error {
@@ -138,49 +102,6 @@ func SetPruningSettings(homePath string, stateRequests bool, keepRecent int, int
 	return nil
 }
 
-// requestPoolSettings retrieves KYVE pool settings by making an GET request to the given endpoint.
-// It reads the response, extracts the relevant settings information and returns it.
-func requestPoolSettings(poolId int, endpoint string) ([2]int, error) {
-	poolEndpoint := endpoint + "/kyve/query/v1beta1/pool/" + strconv.FormatInt(int64(poolId), 10)
-
-	fmt.Println(poolEndpoint)
-
-	response, err := http.Get(poolEndpoint)
-	if err != nil {
-		logger.Error("API is not available", err.Error())
-		return [2]int{}, err
-	}
-
-	responseData, err := io.ReadAll(response.Body)
-	if err != nil {
-		logger.Error("got unexpected response", err.Error())
-		return [2]int{}, err
-	}
-
-	var resp types.SettingsResponse
-	err = json.Unmarshal(responseData, &resp)
-	if err != nil {
-		logger.Error("couldn't unmarshal response", err.Error())
-		return [2]int{}, err
-	}
-
-	uploadInterval := resp.Pool.Data.UploadInterval
-	interval, err := strconv.Atoi(uploadInterval)
-	if err != nil {
-		logger.Error("couldn't convert uploadInterval to int", err.Error())
-		return [2]int{}, err
-	}
-
-	maxBundleSize := resp.Pool.Data.MaxBundleSize
-	size, err := strconv.Atoi(maxBundleSize)
-	if err != nil {
-		logger.Error("couldn't convert maxBundleSize to int", err.Error())
-		return [2]int{}, err
-	}
-
-	return [2]int{size, interval}, nil
-}
-
 // writeUpdatedConfig is responsible for writing the updated pruning settings to a given config file.
 func writeUpdatedConfig(configPath string, pruningSettings []string) error {
 	updatedFile, err := os.Create(configPath)
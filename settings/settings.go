@@ -1,9 +1,13 @@
 package settings
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
+	"cosmossdk.io/log"
+
+	"github.com/KYVENetwork/supervysor/pool"
 	"github.com/KYVENetwork/supervysor/settings/helpers"
 	"github.com/KYVENetwork/supervysor/types"
 )
@@ -24,7 +28,7 @@ var Settings = types.SettingsType{
 // and homePath and sets the seeds value required for the node. It retrieves the pool settings, calculates the
 // keepRecent and maxDifference values, and sets the pruning settings based on these calculated values.
 // If any step encounters an error, it returns the corresponding error message.
-func InitializeSettings(binaryPath string, homePath string, poolId int, stateRequests bool, seeds string, chainId string, fallbackEndpoints string) error {
+func InitializeSettings(binaryPath string, homePath string, stateRequests bool, seeds string, poolClient *pool.Client, logger log.Logger) error {
 	if err := helpers.CheckBinaryPath(binaryPath); err != nil {
 		return fmt.Errorf("could not resolve binary path: %s", err)
 	}
@@ -38,13 +42,12 @@ func InitializeSettings(binaryPath string, homePath string, poolId int, stateReq
 		return fmt.Errorf("seeds are not defined")
 	}
 
-	settings, err := helpers.GetPoolSettings(poolId, chainId, fallbackEndpoints)
+	settings, err := poolClient.GetPoolSettings(context.Background())
 	if err != nil {
 		return fmt.Errorf("could not get pool settings: %s", err)
 	}
 
-	poolSettings.MaxBundleSize = settings[0]
-	poolSettings.UploadInterval = settings[1]
+	poolSettings = settings
 
 	keepRecent := helpers.CalculateKeepRecent(poolSettings.MaxBundleSize, poolSettings.UploadInterval)
 
@@ -66,6 +69,7 @@ func InitializeSettings(binaryPath string, homePath string, poolId int, stateReq
 	if err = helpers.SetPruningSettings(homePath, stateRequests, keepRecent, Settings.Interval); err != nil {
 		return fmt.Errorf("could not set pruning settings: %s", err)
 	}
+	logger.Info("pruning settings applied", "keep-recent", keepRecent, "max-difference", maxDifference, "state-requests", stateRequests)
 
 	return nil
 }
@@ -2,49 +2,61 @@ package store
 
 import (
 	"fmt"
-	"os"
 
 	"cosmossdk.io/log"
 
-	"github.com/KYVENetwork/supervysor/cmd/supervysor/helpers"
-	dbm "github.com/tendermint/tm-db"
+	"github.com/KYVENetwork/supervysor/node/helpers"
 )
 
-func PruneBlocks(home string, untilHeight int64, logger log.Logger) error {
+// PruneBlocks prunes all blocks below untilHeight and returns the number of pruned blocks and the
+// new base height of the blockstore.
+func PruneBlocks(home string, untilHeight int64, logger log.Logger) (blocks uint64, base int64, err error) {
 	config, err := helpers.LoadConfig(home)
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		return 0, 0, fmt.Errorf("failed to load config: %w", err)
 	}
 
 	blockStoreDB, blockStore, err := GetBlockstoreDBs(config)
-	defer func(blockStoreDB dbm.DB) {
-		err = blockStoreDB.Close()
-		if err != nil {
-			logger.Error(err.Error())
-			os.Exit(0)
-		}
-	}(blockStoreDB)
-
 	if err != nil {
-		panic(fmt.Errorf("failed to load blockstore db: %w", err))
+		return 0, 0, fmt.Errorf("failed to load blockstore db: %w", err)
 	}
+	defer func() {
+		if closeErr := blockStoreDB.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close blockstore db: %w", closeErr)
+		}
+	}()
 
-	base := blockStore.Base()
+	base = blockStore.Base()
 
 	logger.Info("blockstore base", "base", base)
 
 	if untilHeight < base {
-		logger.Error("Error: base height is higher than prune height", "base height", base, "until height", untilHeight)
-		os.Exit(0)
+		return 0, base, fmt.Errorf("base height %d is higher than prune height %d", base, untilHeight)
 	}
 
-	blocks, err := blockStore.PruneBlocks(untilHeight)
+	blocks, err = blockStore.PruneBlocks(untilHeight)
 	if err != nil {
-		logger.Error(err.Error())
-		os.Exit(0)
+		return 0, base, fmt.Errorf("failed to prune blocks: %w", err)
 	}
 
-	logger.Info(fmt.Sprintf("Pruned %d blocks, new base height is %d", blocks, blockStore.Base()))
+	logger.Info("pruned blocks", "blocks", blocks, "base", blockStore.Base())
+
+	return blocks, blockStore.Base(), nil
+}
+
+// GetBaseHeight returns the lowest height stored in the blockstore. The node must not be running,
+// since it locks the database.
+func GetBaseHeight(home string) (int64, error) {
+	config, err := helpers.LoadConfig(home)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	blockStoreDB, blockStore, err := GetBlockstoreDBs(config)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load blockstore db: %w", err)
+	}
+	defer blockStoreDB.Close()
 
-	return nil
+	return blockStore.Base(), nil
 }
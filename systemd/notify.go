@@ -0,0 +1,121 @@
+package systemd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Notifier sends state changes to systemd through the socket of the sd_notify protocol. All methods
+// are no-ops if the supervysor isn't run by systemd as a service of Type=notify.
+type Notifier struct {
+	socket   string
+	watchdog time.Duration
+
+	mu sync.Mutex
+}
+
+// NewNotifier reads the notify socket and the watchdog interval from the environment set by systemd.
+func NewNotifier() *Notifier {
+	n := &Notifier{socket: os.Getenv("NOTIFY_SOCKET")}
+
+	// The watchdog only applies to this process if systemd didn't address another PID.
+	if pid := os.Getenv("WATCHDOG_PID"); pid == "" || pid == strconv.Itoa(os.Getpid()) {
+		if usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64); err == nil && usec > 0 {
+			n.watchdog = time.Duration(usec) * time.Microsecond
+		}
+	}
+
+	return n
+}
+
+// Enabled reports whether the supervysor was started by systemd with a notify socket.
+func (n *Notifier) Enabled() bool {
+	return n.socket != ""
+}
+
+// Watchdog returns the interval after which systemd considers the service hung without a ping, or 0
+// if the watchdog isn't enabled.
+func (n *Notifier) Watchdog() time.Duration {
+	return n.watchdog
+}
+
+// Ready tells systemd that the service finished starting up.
+func (n *Notifier) Ready(status string) error {
+	return n.notify("READY=1\nSTATUS=" + status)
+}
+
+// Status updates the status shown by systemctl status.
+func (n *Notifier) Status(status string) error {
+	return n.notify("STATUS=" + status)
+}
+
+// Stopping tells systemd that the service is shutting down.
+func (n *Notifier) Stopping(status string) error {
+	return n.notify("STOPPING=1\nSTATUS=" + status)
+}
+
+// Ping resets the watchdog timer.
+func (n *Notifier) Ping() error {
+	if n.watchdog == 0 {
+		return nil
+	}
+	return n.notify("WATCHDOG=1")
+}
+
+// KeepAlive pings the watchdog in the background until the returned function is called, which is used
+// for long-running operations like pruning that would exceed the watchdog interval otherwise.
+func (n *Notifier) KeepAlive() func() {
+	if n.watchdog == 0 || !n.Enabled() {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(n.watchdog / 2)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				_ = n.Ping()
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+func (n *Notifier) notify(state string) error {
+	if !n.Enabled() {
+		return nil
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	// Abstract sockets are passed with a leading @, which needs to be replaced by a null byte.
+	name := n.socket
+	if name[0] == '@' {
+		name = "\x00" + name[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: name, Net: "unixgram"})
+	if err != nil {
+		return fmt.Errorf("could not connect to notify socket: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err = conn.Write([]byte(state)); err != nil {
+		return fmt.Errorf("could not write to notify socket: %w", err)
+	}
+	return nil
+}
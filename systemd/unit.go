@@ -0,0 +1,64 @@
+package systemd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Unit contains the values of the generated systemd unit file.
+type Unit struct {
+	Description    string
+	User           string
+	ExecStart      string
+	Environment    []string
+	WatchdogSec    int
+	TimeoutStopSec int
+	RestartSec     int
+	LimitNOFILE    int
+}
+
+var unitTemplate = template.Must(template.New("unit").Parse(`[Unit]
+Description={{ .Description }}
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+Type=notify
+NotifyAccess=main
+User={{ .User }}
+ExecStart={{ .ExecStart }}
+{{- range .Environment }}
+Environment="{{ . }}"
+{{- end }}
+Restart=on-failure
+RestartSec={{ .RestartSec }}
+# The node can take a long time until its ABCI endpoint responds, e.g. while replaying blocks.
+TimeoutStartSec=infinity
+# Only the supervysor receives SIGTERM, so it can shut the node down gracefully.
+KillMode=mixed
+TimeoutStopSec={{ .TimeoutStopSec }}
+{{- if gt .WatchdogSec 0 }}
+WatchdogSec={{ .WatchdogSec }}
+{{- end }}
+LimitNOFILE={{ .LimitNOFILE }}
+
+[Install]
+WantedBy=multi-user.target
+`))
+
+// Render returns the content of the unit file.
+func (u Unit) Render() ([]byte, error) {
+	for _, value := range append([]string{u.User, u.ExecStart}, u.Environment...) {
+		if strings.ContainsAny(value, "\n\"") {
+			return nil, fmt.Errorf("invalid value in unit file: %q", value)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := unitTemplate.Execute(&buf, u); err != nil {
+		return nil, fmt.Errorf("could not render unit file: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -16,6 +16,35 @@ var (
 	}
 )
 
+// BuiltinChains maps the chain IDs of all officially supported KYVE chains to their REST endpoints.
+// Additional chains (e.g. devnets or local chains) can be defined in the supervysor config.
+var BuiltinChains = map[string][]string{
+	"kyve-1":     MainnetEndpoints,
+	"kaon-1":     KaonEndpoints,
+	"korellia":   KorelliaEndpoints,
+	"korellia-2": KorelliaEndpoints,
+}
+
 const (
 	BackoffMaxRetries = 15
 )
+
+// Pool status values as returned by the KYVE API (kyve.pool.v1beta1.PoolStatus).
+const (
+	PoolStatusUnspecified         = "POOL_STATUS_UNSPECIFIED"
+	PoolStatusActive              = "POOL_STATUS_ACTIVE"
+	PoolStatusDisabled            = "POOL_STATUS_DISABLED"
+	PoolStatusNoFunds             = "POOL_STATUS_NO_FUNDS"
+	PoolStatusNotEnoughDelegation = "POOL_STATUS_NOT_ENOUGH_DELEGATION"
+	PoolStatusUpgrading           = "POOL_STATUS_UPGRADING"
+)
+
+// PoolStatuses lists all known pool status values in the order of their protobuf enum values.
+var PoolStatuses = []string{
+	PoolStatusUnspecified,
+	PoolStatusActive,
+	PoolStatusDisabled,
+	PoolStatusNoFunds,
+	PoolStatusNotEnoughDelegation,
+	PoolStatusUpgrading,
+}
@@ -1,46 +1,156 @@
 package types
 
 import (
+	"io"
+
 	"github.com/prometheus/client_golang/prometheus"
 	tmCfg "github.com/tendermint/tendermint/config"
 	tmTypes "github.com/tendermint/tendermint/types"
 )
 
 type SupervysorConfig struct {
-	ABCIEndpoint        string
-	BinaryPath          string
-	ChainId             string
-	FallbackEndpoints   string
-	HeightDifferenceMax int
-	HeightDifferenceMin int
-	HomePath            string
-	Interval            int
-	Metrics             bool
-	MetricsPort         int
-	PoolId              int
-	PruningInterval     int
-	Seeds               string
-	StateRequests       bool
+	ABCIEndpoint              string
+	BinaryPath                string
+	ChainId                   string
+	Chains                    []ChainType
+	FallbackEndpoints         string
+	GhostVerifyWindow         int
+	HeightDifferenceMax       int
+	HeightDifferenceMin       int
+	HomePath                  string
+	Hooks                     []HookType
+	Interval                  int
+	LogFormat                 string
+	LogLevel                  string
+	LogMaxAge                 int
+	LogMaxBackups             int
+	LogMaxSize                int
+	Metrics                   bool
+	MetricsPort               int
+	MinDwellTime              int
+	NodeLogs                  bool
+	NodeLogsModeTag           bool
+	NotificationBehindDelay   int
+	NotificationDedupWindow   int
+	NotificationDiskThreshold int
+	NotificationRateLimit     int
+	Notifiers                 []NotifierType
+	PoolEventsEndpoint        string
+	PoolHeightMode            string
+	PoolHeightQuorum          int
+	PoolHeightTolerance       int
+	PoolId                    int
+	PoolOutageGrace           int
+	PoolOutageMax             int
+	PoolRequestRetries        int
+	PoolRequestTimeout        int
+	PoolStatusPolicies        map[string]string
+	PredictiveMargin          int
+	PredictiveSwitching       bool
+	Proxies                   []ProxyType
+	PruningInterval           int
+	SeedLists                 []string
+	Seeds                     string
+	ShutdownTimeout           int
+	StallPolicy               string
+	StallStopWindow           int
+	StallWindow               int
+	StateRequests             bool
 }
 
-type Config = tmCfg.Config
+// HookType configures a shell command which is run before (pre) or after (post) an event.
+type HookType struct {
+	Event   string
+	Phase   string
+	Command string
+	// Timeout in seconds, defaults to 60 seconds.
+	Timeout int
+	// FailClosed aborts the event if a pre hook fails and skips the remaining post hooks if a post hook
+	// fails, otherwise failures are only logged. Failed post hooks never fail the event, which already happened.
+	FailClosed bool
+}
 
-type GenesisDoc = tmTypes.GenesisDoc
+// NotifierType configures a notification sink. Only the fields of the given type are used.
+type NotifierType struct {
+	Type     string
+	Events   []string
+	URL      string
+	Template string
+	Headers  map[string]string
+	Token    string
+	ChatId   string
+	SMTPHost string
+	SMTPPort int
+	Username string
+	Password string
+	From     string
+	To       []string
+}
 
-type HeightResponse struct {
-	Result struct {
-		Response struct {
-			LastBlockHeight string `json:"last_block_height"`
-		} `json:"response"`
-	} `json:"result"`
+// ProxyType configures a reverse proxy in front of an endpoint of the node, e.g. its RPC or REST API.
+type ProxyType struct {
+	// Name is used as label of the proxy metrics, e.g. rpc or api.
+	Name string
+	// Listen is the address the proxy listens on, e.g. :26667.
+	Listen string
+	// Target is the URL of the node endpoint, e.g. http://localhost:26657.
+	Target string
+	// Timeout in seconds requests are held while the node is restarted, defaults to 60 seconds.
+	Timeout int
 }
 
+type ChainType struct {
+	ChainId   string
+	Endpoints []string
+}
+
+type Config = tmCfg.Config
+
+type GenesisDoc = tmTypes.GenesisDoc
+
 type Metrics struct {
 	PoolHeight  prometheus.Gauge
 	NodeHeight  prometheus.Gauge
 	MaxHeight   prometheus.Gauge
 	MinHeight   prometheus.Gauge
 	DataDirSize prometheus.Gauge
+
+	PoolOutageDuration prometheus.Gauge
+	PoolStatus         *prometheus.GaugeVec
+
+	PoolEventsConnected prometheus.Gauge
+
+	GhostModeLeak            prometheus.Gauge
+	GhostModeLeaks           prometheus.Counter
+	GhostIsolationUnverified prometheus.Counter
+
+	PoolVelocity prometheus.Gauge
+	NodeVelocity prometheus.Gauge
+	CatchUpTime  prometheus.Gauge
+
+	PoolEndpointHeight  *prometheus.GaugeVec
+	PoolEndpointLatency *prometheus.GaugeVec
+	PoolEndpointOutlier *prometheus.GaugeVec
+	PoolEndpointErrors  *prometheus.CounterVec
+
+	CurrentMode     *prometheus.GaugeVec
+	ModeSeconds     *prometheus.CounterVec
+	ModeTransitions *prometheus.CounterVec
+	SwitchDowntime  *prometheus.HistogramVec
+
+	PruneRuns      prometheus.Counter
+	PrunedBlocks   prometheus.Counter
+	PruneDuration  prometheus.Histogram
+	BlockstoreBase prometheus.Gauge
+
+	NodeRestarts prometheus.Counter
+	NodeExits    *prometheus.CounterVec
+	NodeStall    prometheus.Gauge
+	NodeStalls   *prometheus.CounterVec
+
+	ProxyRequests        *prometheus.CounterVec
+	ProxyRequestDuration *prometheus.HistogramVec
+	ProxyRetries         *prometheus.CounterVec
 }
 
 type PoolSettingsType struct {
@@ -51,6 +161,12 @@ type PoolSettingsType struct {
 type ProcessType struct {
 	Id        int
 	GhostMode bool
+	// ExitCode is the exit code of the last node process which was shut down, -1 if it was terminated
+	// by a signal.
+	ExitCode int
+	// Output receives stdout and stderr of the node process, which are passed through to the
+	// supervysor's own if it is nil.
+	Output io.Writer
 }
 
 type SettingsResponse struct {
@@ -60,7 +176,9 @@ type SettingsResponse struct {
 			CurrentKey     string `json:"current_key"`
 			UploadInterval string `json:"upload_interval"`
 			MaxBundleSize  string `json:"max_bundle_size"`
+			Disabled       bool   `json:"disabled"`
 		} `json:"data"`
+		Status string `json:"status"`
 	} `json:"pool"`
 }
 